@@ -0,0 +1,113 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/shi-yunsheng/gostar"
+)
+
+// gostar config子命令
+func runConfig(args []string) error {
+	if len(args) == 0 {
+		return errors.New("usage: gostar config <check|encrypt|keygen> [arguments]")
+	}
+
+	switch args[0] {
+	case "check":
+		return runConfigCheck(args[1:])
+	case "encrypt":
+		return runConfigEncrypt(args[1:])
+	case "keygen":
+		return runConfigKeygen(args[1:])
+	default:
+		return fmt.Errorf("unknown config command %q", args[0])
+	}
+}
+
+// gostar config check [-profile name] [file]
+// 校验配置文件，不会连接数据库和Redis
+func runConfigCheck(args []string) error {
+	flags := flag.NewFlagSet("gostar config check", flag.ContinueOnError)
+	profile := flags.String("profile", "", "profile to merge, e.g.: prod (default: $GOSTAR_PROFILE)")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	name := "config.yaml"
+	if flags.NArg() > 0 {
+		name = flags.Arg(0)
+	}
+
+	if err := gostar.CheckConfig(name, *profile); err != nil {
+		return err
+	}
+	fmt.Printf("%s is valid\n", name)
+	return nil
+}
+
+// gostar config encrypt [-key key | -key-file file] [value]
+// 加密配置值，输出ENC(...)，不指定value时从标准输入读取一行，避免明文留在命令历史中
+func runConfigEncrypt(args []string) error {
+	flags := flag.NewFlagSet("gostar config encrypt", flag.ContinueOnError)
+	key := flags.String("key", "", "secret key, base64 or hex encoded (default: $GOSTAR_SECRET_KEY)")
+	keyFile := flags.String("key-file", "", "file containing the secret key (default: $GOSTAR_SECRET_KEY_FILE)")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	var secret [][]byte
+	switch {
+	case *key != "":
+		parsed, err := gostar.ParseSecretKey(*key)
+		if err != nil {
+			return err
+		}
+		secret = append(secret, parsed)
+	case *keyFile != "":
+		data, err := os.ReadFile(*keyFile)
+		if err != nil {
+			return err
+		}
+		parsed, err := gostar.ParseSecretKey(string(data))
+		if err != nil {
+			return err
+		}
+		secret = append(secret, parsed)
+	}
+
+	value := strings.Join(flags.Args(), " ")
+	if flags.NArg() == 0 {
+		fmt.Fprint(os.Stderr, "Value to encrypt: ")
+		line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil && line == "" {
+			return fmt.Errorf("read value failed: %w", err)
+		}
+		value = strings.TrimRight(line, "\r\n")
+	}
+
+	encrypted, err := gostar.EncryptSecret(value, secret...)
+	if err != nil {
+		return err
+	}
+	fmt.Println(encrypted)
+	return nil
+}
+
+// gostar config keygen
+// 生成随机密钥，用于GOSTAR_SECRET_KEY或密钥文件
+func runConfigKeygen(args []string) error {
+	flags := flag.NewFlagSet("gostar config keygen", flag.ContinueOnError)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	key, err := gostar.GenerateSecretKey()
+	if err != nil {
+		return err
+	}
+	fmt.Println(key)
+	return nil
+}
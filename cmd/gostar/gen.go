@@ -0,0 +1,74 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"unicode"
+
+	"github.com/shi-yunsheng/gostar/utils"
+)
+
+// 模型模板数据
+type modelData struct {
+	// 包名
+	Package string
+	// 模型名
+	Name string
+}
+
+// gostar gen <model> [arguments]
+func runGen(args []string) error {
+	if len(args) == 0 {
+		return errors.New("usage: gostar gen model [-dir models] <Name>")
+	}
+
+	switch args[0] {
+	case "model":
+		return runGenModel(args[1:])
+	default:
+		return fmt.Errorf("unknown gen command %q", args[0])
+	}
+}
+
+// gostar gen model [-dir models] <Name>
+// 生成嵌入BaseModel的模型，并在init中注册到自动迁移列表
+func runGenModel(args []string) error {
+	flags := flag.NewFlagSet("gostar gen model", flag.ContinueOnError)
+	dir := flags.String("dir", "models", "directory of the models package")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return errors.New("usage: gostar gen model [-dir models] <Name>")
+	}
+
+	name := flags.Arg(0)
+	if !isExportedIdent(name) {
+		return fmt.Errorf("invalid model name %q, must be an exported Go identifier, e.g.: Article", name)
+	}
+	data := modelData{Package: filepath.Base(*dir), Name: name}
+	// 模型包不存在时同时生成注册函数
+	if _, err := os.Stat(filepath.Join(*dir, "models.go")); os.IsNotExist(err) {
+		if err := writeTemplate(filepath.Join(*dir, "models.go"), "models.go", data); err != nil {
+			return err
+		}
+	}
+
+	return writeTemplate(filepath.Join(*dir, utils.CamelToSnake(name)+".go"), "model.go", data)
+}
+
+// 是否为导出的Go标识符
+func isExportedIdent(name string) bool {
+	for i, r := range name {
+		if i == 0 && !unicode.IsUpper(r) {
+			return false
+		}
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' {
+			return false
+		}
+	}
+	return name != ""
+}
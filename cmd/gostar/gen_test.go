@@ -0,0 +1,28 @@
+package main
+
+import (
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"testing"
+)
+
+func TestGenModelPackage(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "internal", "entity")
+	if err := runGenModel([]string{"-dir", dir, "Article"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := runGenModel([]string{"-dir", dir, "Comment"}); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{"models.go", "article.go", "comment.go"} {
+		file, err := parser.ParseFile(token.NewFileSet(), filepath.Join(dir, name), nil, parser.PackageClauseOnly)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if file.Name.Name != "entity" {
+			t.Errorf("%s: package = %s, want entity", name, file.Name.Name)
+		}
+	}
+}
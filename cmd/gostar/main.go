@@ -0,0 +1,62 @@
+// gostar命令行工具
+package main
+
+import (
+	"fmt"
+	"os"
+	"slices"
+)
+
+// 子命令
+type command struct {
+	// 命令说明
+	usage string
+	// 执行函数，args不包含命令名
+	run func(args []string) error
+}
+
+// 所有子命令
+var commands = map[string]command{
+	"new":    {usage: "Create a new project with config.yaml, main.go and a sample route and model", run: runNew},
+	"routes": {usage: "Print the route table of the app in the current directory", run: runRoutes},
+	"config": {usage: "Manage config files (check, encrypt, keygen)", run: runConfig},
+	"gen":    {usage: "Generate code (model)", run: runGen},
+}
+
+func main() {
+	if len(os.Args) < 2 || os.Args[1] == "-h" || os.Args[1] == "--help" || os.Args[1] == "help" {
+		printUsage()
+		return
+	}
+
+	cmd, ok := commands[os.Args[1]]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n", os.Args[1])
+		printUsage()
+		os.Exit(2)
+	}
+	if err := cmd.run(os.Args[2:]); err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
+	}
+}
+
+// 打印用法
+func printUsage() {
+	fmt.Fprintln(os.Stderr, "Usage: gostar <command> [arguments]")
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "Commands:")
+	for _, name := range sortedCommands() {
+		fmt.Fprintf(os.Stderr, "  %-10s %s\n", name, commands[name].usage)
+	}
+}
+
+// 按名称排序的子命令
+func sortedCommands() []string {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
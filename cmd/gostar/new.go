@@ -0,0 +1,63 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/shi-yunsheng/gostar"
+)
+
+// gostar new [-module path] <name>
+// 创建示例项目，包含go.mod、main.go、routes.go、config.yaml和示例模型
+func runNew(args []string) error {
+	flags := flag.NewFlagSet("gostar new", flag.ContinueOnError)
+	module := flags.String("module", "", "module path (default: the project name)")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return errors.New("usage: gostar new [-module path] <name>")
+	}
+
+	dir := flags.Arg(0)
+	if _, err := os.Stat(dir); err == nil {
+		return fmt.Errorf("%s already exists", dir)
+	}
+	if *module == "" {
+		*module = filepath.Base(dir)
+	}
+	data := map[string]string{"Module": *module}
+
+	files := []struct {
+		path     string
+		template string
+		data     any
+	}{
+		{"go.mod", "go.mod", data},
+		{"main.go", "main.go", data},
+		{"routes.go", "routes.go", data},
+		{filepath.Join("models", "models.go"), "models.go", modelData{Package: "models"}},
+		{filepath.Join("models", "user.go"), "model.go", modelData{Package: "models", Name: "User"}},
+	}
+	for _, file := range files {
+		if err := writeTemplate(filepath.Join(dir, file.path), file.template, file.data); err != nil {
+			return err
+		}
+	}
+	configPath := filepath.Join(dir, "config.yaml")
+	if err := gostar.WriteDefaultConfig(configPath); err != nil {
+		return err
+	}
+	fmt.Println("created", configPath)
+
+	fmt.Println()
+	fmt.Println("Next steps:")
+	fmt.Println("  cd", dir)
+	fmt.Println("  # configure database.default in config.yaml to enable the sample model")
+	fmt.Println("  go mod tidy")
+	fmt.Println("  go run .")
+	return nil
+}
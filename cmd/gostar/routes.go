@@ -0,0 +1,92 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"text/tabwriter"
+
+	"github.com/shi-yunsheng/gostar/router"
+)
+
+// 路由表输出文件的环境变量，与框架中的GOSTAR_ROUTES_FILE一致
+const envRoutesFile = "GOSTAR_ROUTES_FILE"
+
+// gostar routes [-json] [package]
+// 编译并运行应用，应用不会连接数据库和Redis，调用Run时输出路由表并返回gostar.ErrRoutesDumped，不会启动服务
+func runRoutes(args []string) error {
+	flags := flag.NewFlagSet("gostar routes", flag.ContinueOnError)
+	asJSON := flags.Bool("json", false, "print routes as JSON")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	pkg := "."
+	if flags.NArg() > 0 {
+		pkg = flags.Arg(0)
+	}
+
+	file, err := os.CreateTemp("", "gostar-routes-*.json")
+	if err != nil {
+		return err
+	}
+	file.Close()
+	defer os.Remove(file.Name())
+
+	cmd := exec.Command("go", "run", pkg)
+	cmd.Env = append(os.Environ(), envRoutesFile+"="+file.Name())
+	cmd.Stdout = os.Stderr
+	cmd.Stderr = os.Stderr
+	// 应用可能把ErrRoutesDumped当作普通错误处理并以非0状态退出，路由表已写入时忽略退出状态
+	runErr := cmd.Run()
+
+	data, err := os.ReadFile(file.Name())
+	if err != nil {
+		return err
+	}
+	if len(data) == 0 && runErr != nil {
+		return fmt.Errorf("run %s failed: %w", pkg, runErr)
+	}
+	if len(data) == 0 {
+		return fmt.Errorf("no routes reported by %s, make sure it calls app.Run or app.RunWithGracefulShutdown", pkg)
+	}
+	if *asJSON {
+		fmt.Println(string(data))
+		return nil
+	}
+
+	var routes []router.RouteInfo
+	if err := json.Unmarshal(data, &routes); err != nil {
+		return fmt.Errorf("parse routes failed: %w", err)
+	}
+	printRoutes(routes)
+	return nil
+}
+
+// 以表格形式打印路由
+func printRoutes(routes []router.RouteInfo) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "METHOD\tHOST\tPATH\tKIND\tMIDDLEWARE\tSECRET KEY\tADMIN")
+	for _, route := range routes {
+		method := route.Method
+		if method == "" {
+			method = "ANY"
+		}
+		host := route.Host
+		if host == "" {
+			host = "*"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\t%s\t%s\n", method, host, route.Path, route.Kind, route.Middleware, yesNo(route.SecretKey), yesNo(route.Admin))
+	}
+	w.Flush()
+	fmt.Printf("\n%d route(s)\n", len(routes))
+}
+
+// 布尔值显示为yes或no
+func yesNo(value bool) string {
+	if value {
+		return "yes"
+	}
+	return "no"
+}
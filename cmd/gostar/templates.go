@@ -0,0 +1,48 @@
+package main
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"go/format"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+// 代码模板
+//
+//go:embed templates/*.tmpl
+var templateFS embed.FS
+
+var templates = template.Must(template.ParseFS(templateFS, "templates/*.tmpl"))
+
+// 渲染模板并写入文件，Go源文件会格式化，文件已存在时返回错误
+func writeTemplate(path string, name string, data any) error {
+	if _, err := os.Stat(path); err == nil {
+		return fmt.Errorf("%s already exists", path)
+	}
+
+	var buf bytes.Buffer
+	if err := templates.ExecuteTemplate(&buf, name+".tmpl", data); err != nil {
+		return err
+	}
+	content := buf.Bytes()
+	if strings.HasSuffix(path, ".go") {
+		formatted, err := format.Source(content)
+		if err != nil {
+			return fmt.Errorf("format %s failed: %w", path, err)
+		}
+		content = formatted
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, content, 0644); err != nil {
+		return err
+	}
+	fmt.Println("created", path)
+	return nil
+}
@@ -1,12 +1,14 @@
 package gostar
 
 import (
+	"fmt"
 	"maps"
 	"os"
 	"reflect"
 	"slices"
 	"strings"
 
+	"github.com/shi-yunsheng/gostar/logger"
 	"github.com/shi-yunsheng/gostar/model"
 
 	"gopkg.in/yaml.v3"
@@ -18,30 +20,141 @@ type config struct {
 	Debug bool `yaml:"debug"`
 	// 允许的来源
 	AllowedOrigins []string `yaml:"allowed_origins"`
-	// 绑定地址和端口
-	Bind string `yaml:"bind"`
+	// 绑定地址，可以是单个地址或地址列表，支持host:port、unix:/path、fd:3和systemd
+	Bind bindList `yaml:"bind"`
+	// 管理绑定地址，只提供标记为Admin的路由，格式同Bind
+	AdminBind bindList `yaml:"admin_bind"`
+	// Unix域套接字配置
+	UnixSocket unixSocketConfig `yaml:"unix_socket"`
+	// TLS配置
+	TLS tlsConfig `yaml:"tls"`
+	// HTTP服务器配置
+	Server serverConfig `yaml:"server"`
+	// 健康检查配置
+	Health healthConfig `yaml:"health"`
+	// 零停机重启配置
+	Restart restartConfig `yaml:"restart"`
+	// 优雅关闭的最长等待时间，如：30s, 1m
+	ShutdownTimeout string `yaml:"shutdown_timeout"`
+	// 优雅关闭时就绪检查返回失败后、停止接收请求前的等待时间，如：5s，启用健康检查时默认5s，否则默认不等待
+	ShutdownDelay string `yaml:"shutdown_delay"`
 	// 日志配置
 	Log logConfig `yaml:"log"`
 	// 时区
 	Timezone string `yaml:"timezone"`
-	// 语言
+	// 语言，请求头Accept-Language中没有支持的语言时使用，如：zh-CN, en-US
 	Lang string `yaml:"lang"`
 	// 数据库配置
 	Database map[string]model.DBConfig `yaml:"database"`
 	// Redis配置
 	Redis map[string]model.RedisConfig `yaml:"redis"`
+	// 是否开启配置热加载，开启后配置文件变更时会自动重新加载日志、跨域和自定义配置
+	HotReload bool `yaml:"hot_reload"`
 	// 自定义配置
 	Custom map[string]any
+	// 所有配置（框架配置和自定义配置）
+	all map[string]any `yaml:"-"`
+	// 配置来源文件
+	sources []configSource `yaml:"-"`
+	// 解析时的类型错误
+	decodeErrs ConfigErrors `yaml:"-"`
 }
 
 // 生成默认配置
 func generateDefaultConfig(configName string) {
+	_ = WriteDefaultConfig(configName)
+}
+
+// 将默认配置写入指定文件，文件已存在时会被覆盖
+func WriteDefaultConfig(configName string) error {
 	const defaultConfig = `# 调试模式，开启后会输出详细的调试信息
 debug: false
 
-# 服务绑定地址和端口
+# 是否开启配置热加载，开启后修改本文件会自动重新加载日志、跨域和自定义配置，
+# 绑定地址、数据库等配置仍需重启后生效
+#hot_reload: false
+
+# 可通过环境变量 GOSTAR_PROFILE 或 NewWithProfile 指定环境，如 prod，
+# 此时会在本文件之后深度合并 config.prod.yaml 中的配置
+
+# 配置值支持引用环境变量：${VAR} 或 ${VAR:-default}
+# 也可以使用 GOSTAR_ 前缀的环境变量覆盖任意配置，层级之间用双下划线分隔，
+# 例如：GOSTAR_DATABASE__DEFAULT__PASSWORD=secret、GOSTAR_UPLOAD__MAXSIZE=1024
+
+# 服务绑定地址和端口，可以是单个地址或地址列表，支持格式：
+#   host:port            监听TCP地址
+#   unix:/path/app.sock  监听Unix域套接字
+#   fd:3                 使用继承的文件描述符
+#   systemd              使用systemd套接字激活传递的文件描述符（LISTEN_FDS），systemd:name 只使用指定名称的文件描述符
 bind: 0.0.0.0:8000
 
+# 管理绑定地址，只提供路由中标记为 Admin 的路由，这些路由不会在 bind 地址上提供，格式同 bind
+#admin_bind: 127.0.0.1:9000
+
+# Unix域套接字文件的权限和所有者
+#unix_socket:
+#    mode: "0660"
+#    owner: www-data
+#    group: www-data
+
+# TLS配置，启用后服务将以HTTPS方式运行
+#tls:
+#    enable: true
+#    # 证书和私钥文件路径
+#    cert_file: certs/server.crt
+#    key_file: certs/server.key
+#    # 最低TLS版本，支持：1.0, 1.1, 1.2, 1.3，默认1.2
+#    min_version: "1.2"
+#    # 允许的加密套件，为空则使用Go默认值
+#    cipher_suites:
+#      - TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256
+#    # 客户端CA证书，设置后开启双向认证（mTLS）
+#    client_ca_file: certs/ca.crt
+#    # 客户端证书校验方式：require（必须提供），optional（提供时校验），默认require
+#    client_auth: require
+#    # HTTP重定向监听地址，该地址上的HTTP请求将被重定向到HTTPS
+#    redirect_bind: 0.0.0.0:80
+
+# HTTP服务器配置，时长支持格式如：10s, 1m，大小支持单位：B, KB, MB, GB
+#server:
+#    # 读取整个请求的超时时间，默认不限制
+#    read_timeout: 30s
+#    # 读取请求头的超时时间，默认10s
+#    read_header_timeout: 10s
+#    # 写入响应的超时时间，默认不限制
+#    write_timeout: 30s
+#    # 长连接空闲超时时间，默认120s
+#    idle_timeout: 120s
+#    # 请求头最大大小，默认1MB
+#    max_header_bytes: 1MB
+#    # 请求体最大大小，超出时返回413，默认32MB，设置为None时不限制
+#    max_body_size: 32MB
+#    # 是否禁用长连接
+#    disable_keep_alive: false
+
+# 健康检查配置，启用后提供存活检查和就绪检查接口，就绪检查会检查所有数据库和Redis连接
+#health:
+#    enable: true
+#    # 存活检查路径，默认/healthz
+#    liveness_path: /healthz
+#    # 就绪检查路径，默认/readyz
+#    readiness_path: /readyz
+#    # 单项检查的超时时间，默认3s
+#    timeout: 3s
+
+# 零停机重启配置，启用后使用RunWithGracefulShutdown运行时，收到SIGHUP信号会启动新的可执行文件并传递监听的套接字，
+# 新进程就绪后当前进程再优雅关闭，升级二进制文件时端口不会中断
+#restart:
+#    enable: true
+#    # 等待新进程就绪的最长时间，超时后终止新进程并继续使用当前进程，默认30s
+#    ready_timeout: 30s
+
+# 优雅关闭的最长等待时间，超时后将强制关闭剩余连接，默认30s
+#shutdown_timeout: 30s
+# 优雅关闭时就绪检查返回503后、停止接收新请求前的等待时间，使负载均衡有时间摘除实例，
+# 启用健康检查时默认5s，否则默认不等待，设置为0s关闭等待
+#shutdown_delay: 5s
+
 # 允许的来源
 allowed_origins:
   - "*"
@@ -64,10 +177,11 @@ log:
 # 时区设置，默认使用亚洲/上海时区
 #timezone: Asia/Shanghai
 
-# 语言设置，默认使用中文
+# 语言设置，用于框架的错误信息和校验信息，请求头Accept-Language中没有支持的语言时使用，内置zh-CN和en-US，默认en-US
 #lang: zh-CN
 
 # 数据库配置，可以配置多个数据库连接
+# 密码等敏感配置可以使用 gostar config encrypt 加密为 ENC(...)，密钥通过环境变量 GOSTAR_SECRET_KEY 提供
 # 示例：
 # database:
 #   default:
@@ -101,40 +215,142 @@ redis:
 #     - "image/png"
 # features:
 #   enable_cache: true`
-	_ = os.WriteFile(configName, []byte(defaultConfig), 0644)
+	return os.WriteFile(configName, []byte(defaultConfig), 0644)
+}
+
+// 校验配置文件，不会生成默认配置，也不会连接数据库和Redis，configName为空时使用config.yaml，profile为空时使用GOSTAR_PROFILE
+func CheckConfig(configName string, profile ...string) error {
+	name := getConfigName()
+	if configName != "" {
+		name = getConfigName(configName)
+	}
+	if fileInfo, err := os.Stat(name); err != nil {
+		return fmt.Errorf("read config file %s failed: %w", name, err)
+	} else if fileInfo.IsDir() {
+		return fmt.Errorf("%s is a directory, please check the file path", name)
+	}
+
+	var p string
+	if len(profile) > 0 {
+		p = profile[0]
+	}
+	config, err := loadConfig(&configReader{}, name, getProfile(p))
+	if err != nil {
+		return err
+	}
+	return config.validate()
+}
+
+// 配置来源，用于在校验错误中定位文件和行号
+type configSource struct {
+	name string
+	node *yaml.Node
 }
 
-// 获取配置
-func getConfig(name ...string) *config {
+// 读取配置文件并解析到map，会展开${VAR}和${VAR:-default}环境变量，根据扩展名识别YAML、JSON和TOML格式
+func readConfigFile(reader *configReader, configName string) (map[string]any, configSource, error) {
+	source := configSource{name: configName, node: &yaml.Node{}}
+
+	data, err := reader.readFile(configName)
+	if err != nil {
+		return nil, source, fmt.Errorf("read config file %s failed: %w", configName, err)
+	}
+
+	node, err := parseConfigData(configName, data)
+	if err != nil {
+		return nil, source, fmt.Errorf("parse config file %s failed: %w", configName, err)
+	}
+	expandEnv(node)
+	source.node = node
+	var allConfig map[string]any
+	if source.node.Kind != 0 {
+		if err := source.node.Decode(&allConfig); err != nil {
+			return nil, source, fmt.Errorf("parse config file %s failed: %w", configName, err)
+		}
+	}
+	if allConfig == nil {
+		allConfig = make(map[string]any)
+	}
+	return allConfig, source, nil
+}
+
+// 获取配置文件名，默认config.yaml，没有.yaml、.yml、.json或.toml扩展名时添加.yaml
+func getConfigName(name ...string) string {
 	configName := "config.yaml"
 	if len(name) > 0 {
 		configName = name[0]
-		// 如果配置名不以.yaml结尾，添加它
-		if !strings.HasSuffix(configName, ".yaml") {
+		if !hasConfigExt(configName) {
 			configName = configName + ".yaml"
 		}
 	}
+	return configName
+}
 
-	if fileInfo, err := os.Stat(configName); os.IsNotExist(err) {
-		generateDefaultConfig(configName)
-	} else if fileInfo.IsDir() {
-		panic(configName + " is a directory, please check the file path")
+// 获取配置，本地YAML配置文件不存在时生成默认配置，profile不为空时会在基础配置之上深度合并对应环境的配置文件
+func getConfig(reader *configReader, configName string, profile string) (*config, error) {
+	if reader.isLocal() {
+		if fileInfo, err := os.Stat(configName); os.IsNotExist(err) {
+			if isConfigGenerationDisabled() {
+				return nil, fmt.Errorf("config file %s not found and config generation is disabled", configName)
+			}
+			// 默认配置为YAML格式，其他格式不自动生成
+			if getConfigFormat(configName) != configFormatYAML {
+				return nil, fmt.Errorf("config file %s not found", configName)
+			}
+			generateDefaultConfig(configName)
+		} else if err != nil {
+			return nil, fmt.Errorf("read config file %s failed: %w", configName, err)
+		} else if fileInfo.IsDir() {
+			return nil, fmt.Errorf("%s is a directory, please check the file path", configName)
+		}
 	}
 
-	data, err := os.ReadFile(configName)
+	return loadConfig(reader, configName, profile)
+}
+
+// 加载配置，不会生成默认配置
+func loadConfig(reader *configReader, configName string, profile string) (*config, error) {
+	// 第一次解析：解析到map（所有配置）
+	allConfig, source, err := readConfigFile(reader, configName)
 	if err != nil {
-		panic("read config file failed: " + err.Error())
+		return nil, err
+	}
+	sources := []configSource{source}
+	// 合并环境配置文件，环境配置文件不会自动生成
+	if profile != "" {
+		profileName := getProfileConfigName(configName, profile)
+		if reader.exists(profileName) {
+			profileConfig, profileSource, err := readConfigFile(reader, profileName)
+			if err != nil {
+				return nil, err
+			}
+			mergeConfig(allConfig, profileConfig)
+			sources = append(sources, profileSource)
+		} else {
+			logger.W("Profile config file %s not found, using %s only", profileName, configName)
+		}
 	}
-	// 第一次解析：解析到结构体（框架配置）
+	// 使用GOSTAR_前缀的环境变量覆盖配置
+	applyEnvOverrides(allConfig)
+	// 解密ENC(...)格式的加密值，解密错误在校验时与其他错误一起返回
+	secretErrs := decryptConfig(allConfig, sources)
+	data, err := yaml.Marshal(allConfig)
+	if err != nil {
+		return nil, fmt.Errorf("parse config file %s failed: %w", configName, err)
+	}
+	// 第二次解析：解析到结构体（框架配置）
+	// 类型错误不会中断解析，在校验时与其他错误一起返回
 	config := &config{}
 	if err := yaml.Unmarshal(data, config); err != nil {
-		panic("parse config file failed: " + err.Error())
-	}
-	// 第二次解析：解析到map（所有配置）
-	var allConfig map[string]any
-	if err := yaml.Unmarshal(data, &allConfig); err != nil {
-		panic("parse config file failed: " + err.Error())
+		decodeErrs, ok := explainDecodeError(sources, err)
+		if !ok {
+			return nil, fmt.Errorf("parse config file %s failed: %w", configName, err)
+		}
+		config.decodeErrs = decodeErrs
 	}
+	config.decodeErrs = append(config.decodeErrs, secretErrs...)
+	config.all = allConfig
+	config.sources = sources
 	// 通过反射获取框架字段名
 	knownFields := getStructYamlTags(config)
 	// 提取自定义配置（删除框架已知字段）
@@ -149,7 +365,7 @@ func getConfig(name ...string) *config {
 		maps.Copy(config.Custom, customFromYaml)
 	}
 
-	return config
+	return config, nil
 }
 
 // 使用反射获取结构体的yaml标签
@@ -216,13 +432,14 @@ func getConfigValue(custom map[string]any, path string, defaultVal ...any) any {
 
 // 通过路径获取自定义配置（支持嵌套键，如 "upload.maxsize"）
 func (g *goStar) GetConfig(path string, defaultVal ...any) any {
-	if g.config == nil {
+	config := g.config.Load()
+	if config == nil {
 		if len(defaultVal) > 0 {
 			return defaultVal[0]
 		}
 		return nil
 	}
-	return getConfigValue(g.config.Custom, path, defaultVal...)
+	return getConfigValue(config.Custom, path, defaultVal...)
 }
 
 // 通过路径获取自定义配置字符串
@@ -252,8 +469,18 @@ func (g *goStar) GetConfigInt(path string, defaultVal ...int) int {
 		}
 		return 0
 	}
-	if num, ok := val.(int); ok {
-		return num
+	// YAML可能解析为int、int64、uint64或float64
+	switch v := val.(type) {
+	case int:
+		return v
+	case int64:
+		return int(v)
+	case uint64:
+		return int(v)
+	case float64:
+		if v == float64(int(v)) {
+			return int(v)
+		}
 	}
 	if len(defaultVal) > 0 {
 		return defaultVal[0]
@@ -294,6 +521,10 @@ func (g *goStar) GetConfigFloat(path string, defaultVal ...float64) float64 {
 		return v
 	case int:
 		return float64(v)
+	case int64:
+		return float64(v)
+	case uint64:
+		return float64(v)
 	case float32:
 		return float64(v)
 	}
@@ -0,0 +1,140 @@
+package gostar
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/mitchellh/mapstructure"
+	"github.com/shi-yunsheng/gostar/date"
+)
+
+// 将自定义配置解析到结构体，path支持嵌套键，如："upload"，为空时解析全部自定义配置
+// 字段名优先使用mapstructure标签，没有mapstructure标签时使用yaml标签，支持数字类型之间的宽松转换，
+// time.Duration字段支持"30s"、"1h30m"、"7d"等格式。
+// 解析后使用 github.com/go-playground/validator/v10 的validate标签进行校验，返回所有校验失败的字段
+func (g *goStar) GetConfigInto(path string, out any) error {
+	config := g.config.Load()
+	if config == nil {
+		return errors.New("config not loaded")
+	}
+
+	var value any = config.Custom
+	if path != "" {
+		value = getConfigValue(config.Custom, path)
+		if value == nil {
+			return fmt.Errorf("config %s not found", path)
+		}
+	}
+
+	return decodeConfig(path, value, out)
+}
+
+// 将当前GoStar实例的自定义配置解析为指定类型，用法参考GetConfigInto
+// 例如：uploadConfig, err := gostar.ConfigSection[UploadConfig]("upload")
+func ConfigSection[T any](path string) (T, error) {
+	var out T
+	if instance == nil {
+		return out, errors.New("gostar not initialized")
+	}
+	err := instance.GetConfigInto(path, &out)
+	return out, err
+}
+
+// 解码配置并校验
+func decodeConfig(path string, value any, out any) error {
+	rv := reflect.ValueOf(out)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return errors.New("out must be a non-nil pointer")
+	}
+
+	tagName := getConfigTagName(rv.Type().Elem())
+	decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		DecodeHook: mapstructure.ComposeDecodeHookFunc(
+			durationDecodeHook,
+			mapstructure.StringToSliceHookFunc(","),
+		),
+		WeaklyTypedInput: true,
+		TagName:          tagName,
+		Result:           out,
+	})
+	if err != nil {
+		return err
+	}
+	if err := decoder.Decode(value); err != nil {
+		return fmt.Errorf("decode config %s failed: %w", path, err)
+	}
+
+	return validateConfig(path, out, tagName)
+}
+
+// 获取结构体使用的标签名，如果有字段使用了mapstructure标签则使用mapstructure，否则使用yaml
+func getConfigTagName(t reflect.Type) string {
+	if t.Kind() != reflect.Struct {
+		return "yaml"
+	}
+	for i := 0; i < t.NumField(); i++ {
+		if _, ok := t.Field(i).Tag.Lookup("mapstructure"); ok {
+			return "mapstructure"
+		}
+	}
+	return "yaml"
+}
+
+// 将字符串解析为time.Duration，支持date.ParseTimeDuration的所有格式
+func durationDecodeHook(from reflect.Type, to reflect.Type, data any) (any, error) {
+	if from.Kind() != reflect.String || to != reflect.TypeOf(time.Duration(0)) {
+		return data, nil
+	}
+	return date.ParseTimeDuration(data.(string))
+}
+
+// 使用validator校验配置，返回所有校验失败的字段
+func validateConfig(path string, out any, tagName string) error {
+	t := reflect.TypeOf(out).Elem()
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+
+	validate := validator.New()
+	// 错误信息中使用配置中的键名
+	validate.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name, _, _ := strings.Cut(field.Tag.Get(tagName), ",")
+		if name == "-" {
+			return ""
+		}
+		if name == "" {
+			return field.Name
+		}
+		return name
+	})
+
+	err := validate.Struct(out)
+	if err == nil {
+		return nil
+	}
+
+	var validationErrors validator.ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		return err
+	}
+
+	errs := make([]error, 0, len(validationErrors))
+	for _, fieldErr := range validationErrors {
+		// 去掉命名空间中的结构体名，替换为配置路径
+		_, field, _ := strings.Cut(fieldErr.Namespace(), ".")
+		if path != "" {
+			field = path + "." + field
+		}
+		if fieldErr.Param() != "" {
+			errs = append(errs, fmt.Errorf("config %s failed on '%s=%s' validation, got: %v", field, fieldErr.Tag(), fieldErr.Param(), fieldErr.Value()))
+		} else {
+			errs = append(errs, fmt.Errorf("config %s failed on '%s' validation, got: %v", field, fieldErr.Tag(), fieldErr.Value()))
+		}
+	}
+
+	return errors.Join(errs...)
+}
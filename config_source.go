@@ -0,0 +1,170 @@
+package gostar
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// 支持的配置格式
+const (
+	configFormatYAML = "yaml"
+	configFormatJSON = "json"
+	configFormatTOML = "toml"
+)
+
+// 配置文件扩展名与格式的映射
+var configFormats = map[string]string{
+	".yaml": configFormatYAML,
+	".yml":  configFormatYAML,
+	".json": configFormatJSON,
+	".toml": configFormatTOML,
+}
+
+// 根据扩展名获取配置格式，无法识别时使用YAML
+func getConfigFormat(name string) string {
+	if format, ok := configFormats[strings.ToLower(filepath.Ext(name))]; ok {
+		return format
+	}
+	return configFormatYAML
+}
+
+// 是否为支持的配置文件扩展名
+func hasConfigExt(name string) bool {
+	_, ok := configFormats[strings.ToLower(filepath.Ext(name))]
+	return ok
+}
+
+// 配置读取器，决定从哪里读取配置文件
+type configReader struct {
+	// 配置所在的文件系统，为nil时读取本地文件
+	fsys fs.FS
+	// 通过io.Reader提供的配置名，设置后只能读取该配置
+	name string
+	// 通过io.Reader提供的配置内容
+	data []byte
+}
+
+// 是否从本地文件读取配置
+func (r *configReader) isLocal() bool {
+	return r.fsys == nil && r.data == nil
+}
+
+// 读取配置文件
+func (r *configReader) readFile(name string) ([]byte, error) {
+	switch {
+	case r.data != nil:
+		if name != r.name {
+			return nil, fs.ErrNotExist
+		}
+		return r.data, nil
+	case r.fsys != nil:
+		return fs.ReadFile(r.fsys, name)
+	default:
+		return os.ReadFile(name)
+	}
+}
+
+// 获取配置文件信息
+func (r *configReader) stat(name string) (fs.FileInfo, error) {
+	switch {
+	case r.data != nil:
+		return nil, fs.ErrNotExist
+	case r.fsys != nil:
+		return fs.Stat(r.fsys, name)
+	default:
+		return os.Stat(name)
+	}
+}
+
+// 判断配置文件是否存在
+func (r *configReader) exists(name string) bool {
+	if r.data != nil {
+		return name == r.name
+	}
+	info, err := r.stat(name)
+	return err == nil && !info.IsDir()
+}
+
+// 按格式解析配置内容为YAML节点，JSON和TOML会转换为等价的YAML节点
+func parseConfigData(name string, data []byte) (*yaml.Node, error) {
+	node := &yaml.Node{}
+
+	switch getConfigFormat(name) {
+	case configFormatJSON:
+		var value any
+		if err := json.Unmarshal(data, &value); err != nil {
+			return nil, err
+		}
+		// JSON是YAML的子集，优先使用YAML解析以保留行号
+		if err := yaml.Unmarshal(data, node); err != nil {
+			node = &yaml.Node{}
+			if err := node.Encode(value); err != nil {
+				return nil, err
+			}
+		}
+	case configFormatTOML:
+		var value map[string]any
+		if err := toml.Unmarshal(data, &value); err != nil {
+			return nil, err
+		}
+		if err := node.Encode(value); err != nil {
+			return nil, err
+		}
+	default:
+		if err := yaml.Unmarshal(data, node); err != nil {
+			return nil, err
+		}
+	}
+
+	return node, nil
+}
+
+// 从文件系统创建GoStar实例，如：embed.FS、os.DirFS，根据name的扩展名识别配置格式，支持YAML、JSON和TOML
+// 不会自动生成默认配置，环境配置文件（如：config.prod.yaml）同样从该文件系统读取
+func NewFromFS(fsys fs.FS, name string) (*goStar, error) {
+	if fsys == nil {
+		return nil, errors.New("config fs is nil")
+	}
+	if !fs.ValidPath(name) {
+		return nil, fmt.Errorf("invalid config path %s in fs", name)
+	}
+	return newGoStar(&configReader{fsys: fsys}, "", path.Clean(name), false)
+}
+
+// 从io.Reader创建GoStar实例，name用于识别配置格式和错误提示，如："config.json"
+// 配置只读取一次，不支持环境配置文件和配置热加载
+func NewFromReader(r io.Reader, name string) (*goStar, error) {
+	return newFromReader(r, name, false)
+}
+
+// 从io.Reader创建独立的GoStar实例，用法与NewFromReader相同，但即使是第一个实例也不会成为默认实例，
+// 拥有独立的日志器、数据库和Redis连接，关闭时不会影响默认实例和包级别函数，适用于测试
+func NewIsolatedFromReader(r io.Reader, name string) (*goStar, error) {
+	return newFromReader(r, name, true)
+}
+
+// 从io.Reader读取配置并创建GoStar实例
+func newFromReader(r io.Reader, name string, isolated bool) (*goStar, error) {
+	if r == nil {
+		return nil, errors.New("config reader is nil")
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("read config %s failed: %w", name, err)
+	}
+	// 内容为空时也需要非nil，以区分本地文件
+	if data == nil {
+		data = []byte{}
+	}
+	return newGoStar(&configReader{name: name, data: data}, "", name, isolated)
+}
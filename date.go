@@ -2,9 +2,12 @@ package gostar
 
 import "github.com/shi-yunsheng/gostar/date"
 
-// 初始化日期时区配置
-func (g *goStar) initDate() {
-	config := g.config.Timezone
+// 初始化日期时区配置，时区为进程级配置，只由默认实例设置
+func (g *goStar) initDate() error {
+	if !g.isDefault {
+		return nil
+	}
+	config := g.config.Load().Timezone
 
-	date.SetCurrentTimezone(config)
+	return date.SetCurrentTimezoneE(config)
 }
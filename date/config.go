@@ -6,17 +6,25 @@ var (
 	currentTimezone = "Asia/Shanghai"
 )
 
-// 设置当前时区，默认Asia/Shanghai
+// 设置当前时区，默认Asia/Shanghai，失败时panic
 func SetCurrentTimezone(timezone string) {
+	if err := SetCurrentTimezoneE(timezone); err != nil {
+		panic(err)
+	}
+}
+
+// 设置当前时区，默认Asia/Shanghai，失败时返回错误
+func SetCurrentTimezoneE(timezone string) error {
 	if timezone == "" {
 		timezone = "Asia/Shanghai"
 	}
-	currentTimezone = timezone
 	loc, err := time.LoadLocation(timezone)
 	if err != nil {
-		panic(err)
+		return err
 	}
+	currentTimezone = timezone
 	time.Local = loc
+	return nil
 }
 
 // 获取当前时区，默认Asia/Shanghai
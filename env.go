@@ -0,0 +1,169 @@
+package gostar
+
+import (
+	"os"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// 环境变量覆盖配置的前缀，层级之间使用双下划线分隔，如：GOSTAR_DATABASE__DEFAULT__PASSWORD
+const envOverridePrefix = "GOSTAR_"
+
+// 框架自身使用的环境变量，不会用于覆盖配置
+var frameworkEnvs = []string{
+	envProfile,
+	envDisableConfigGeneration,
+	envInheritedListeners,
+	envRestartReadyFD,
+	envSecretKey,
+	envSecretKeyFile,
+	envRoutesFile,
+}
+
+// 匹配${VAR}和${VAR:-default}
+var envVarRegex = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)(?::-([^}]*))?\}`)
+
+// 展开已解析配置中字符串值的环境变量，不会展开键名和注释，展开后的值不会再被当作YAML解析
+// ${VAR}：替换为环境变量VAR的值，未设置时为空字符串
+// ${VAR:-default}：环境变量VAR未设置或为空时使用default
+// 未加引号的值展开后按YAML标量重新识别类型（如：port: ${DB_PORT}展开为整数）
+func expandEnv(node *yaml.Node) {
+	switch node.Kind {
+	case yaml.DocumentNode, yaml.SequenceNode:
+		for _, child := range node.Content {
+			expandEnv(child)
+		}
+	case yaml.MappingNode:
+		for i := 1; i < len(node.Content); i += 2 {
+			expandEnv(node.Content[i])
+		}
+	case yaml.ScalarNode:
+		if node.ShortTag() != "!!str" || !envVarRegex.MatchString(node.Value) {
+			return
+		}
+		node.Value = expandEnvString(node.Value)
+		if node.Style&(yaml.SingleQuotedStyle|yaml.DoubleQuotedStyle|yaml.LiteralStyle|yaml.FoldedStyle) == 0 {
+			node.Tag = ""
+		}
+	}
+}
+
+// 展开字符串中的环境变量
+func expandEnvString(value string) string {
+	return envVarRegex.ReplaceAllStringFunc(value, func(match string) string {
+		groups := envVarRegex.FindStringSubmatch(match)
+		env, ok := os.LookupEnv(groups[1])
+		if (!ok || env == "") && strings.Contains(match, ":-") {
+			return groups[2]
+		}
+		return env
+	})
+}
+
+// 使用GOSTAR_前缀的环境变量覆盖配置
+// 环境变量名去掉前缀后以双下划线分隔层级，键名不区分大小写
+// 框架配置中非字符串类型的字段按YAML标量解析（如：true、8080），其他值（包括自定义配置）保留原始字符串
+func applyEnvOverrides(allConfig map[string]any) {
+	for _, env := range os.Environ() {
+		key, value, ok := strings.Cut(env, "=")
+		if !ok || !strings.HasPrefix(key, envOverridePrefix) {
+			continue
+		}
+		// 跳过框架自身使用的环境变量
+		if slices.Contains(frameworkEnvs, key) {
+			continue
+		}
+
+		path := strings.Split(strings.TrimPrefix(key, envOverridePrefix), "__")
+		if len(path) == 0 || path[0] == "" {
+			continue
+		}
+
+		var parsed any = value
+		if typ := configFieldType(path); typ != nil && typ.Kind() != reflect.String && typ.Kind() != reflect.Interface {
+			parsed = parseEnvValue(value)
+		}
+		setConfigByPath(allConfig, path, parsed)
+	}
+}
+
+// 按YAML标量解析环境变量值，解析失败时按字符串处理
+func parseEnvValue(value string) any {
+	var parsed any
+	if err := yaml.Unmarshal([]byte(value), &parsed); err != nil || parsed == nil {
+		return value
+	}
+	// 只接受标量，避免将普通字符串误解析为映射或列表
+	switch parsed.(type) {
+	case map[string]any, []any:
+		return value
+	}
+	return parsed
+}
+
+// 获取配置路径对应的框架配置字段类型，路径不属于框架配置（如自定义配置）时返回nil
+func configFieldType(path []string) reflect.Type {
+	typ := reflect.TypeFor[config]()
+	for _, segment := range path {
+		switch typ.Kind() {
+		case reflect.Map:
+			typ = typ.Elem()
+		case reflect.Struct:
+			field, ok := findYamlField(typ, segment)
+			if !ok {
+				return nil
+			}
+			typ = field.Type
+		default:
+			return nil
+		}
+		for typ.Kind() == reflect.Pointer {
+			typ = typ.Elem()
+		}
+	}
+	return typ
+}
+
+// 查找yaml标签与name相同的字段（不区分大小写）
+func findYamlField(typ reflect.Type, name string) (reflect.StructField, bool) {
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		tag, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+		if tag != "" && tag != "-" && strings.EqualFold(tag, name) {
+			return field, true
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+// 按路径设置配置值，不存在的中间层级会自动创建
+func setConfigByPath(current map[string]any, path []string, value any) {
+	for i, segment := range path {
+		key := findConfigKey(current, segment)
+		if i == len(path)-1 {
+			current[key] = value
+			return
+		}
+
+		next, ok := current[key].(map[string]any)
+		if !ok {
+			next = make(map[string]any)
+			current[key] = next
+		}
+		current = next
+	}
+}
+
+// 查找配置中已存在的键（不区分大小写），不存在时返回小写键名
+func findConfigKey(m map[string]any, segment string) string {
+	for key := range m {
+		if strings.EqualFold(key, segment) {
+			return key
+		}
+	}
+	return strings.ToLower(segment)
+}
@@ -0,0 +1,85 @@
+package gostar
+
+import (
+	"testing"
+)
+
+func TestEnvOverrides(t *testing.T) {
+	t.Setenv("GOSTAR_DEBUG", "true")
+	t.Setenv("GOSTAR_DATABASE__DEFAULT__PASSWORD", "0123")
+	t.Setenv("GOSTAR_DATABASE__DEFAULT__PORT", "3307")
+	t.Setenv("GOSTAR_DATABASE__DEFAULT__USER", "yes")
+	t.Setenv("GOSTAR_SHUTDOWN_TIMEOUT", "1e3")
+	t.Setenv("GOSTAR_APP__CODE", "0123")
+	t.Setenv("GOSTAR_APP__ENABLED", "no")
+	t.Setenv("GOSTAR_APP__NEW", "1e3")
+
+	data := []byte("bind: 127.0.0.1:8000\ndatabase:\n  default:\n    driver: mysql\n    password: secret\n    port: 3306\napp:\n  code: abc\n")
+	c, err := loadConfig(&configReader{name: "config.yaml", data: data}, "config.yaml", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(c.decodeErrs) > 0 {
+		t.Fatal(c.decodeErrs)
+	}
+
+	if !c.Debug {
+		t.Error("debug = false, want true")
+	}
+	db := c.Database["default"]
+	if db.Password != "0123" {
+		t.Errorf("password = %q, want %q", db.Password, "0123")
+	}
+	if db.Port != 3307 {
+		t.Errorf("port = %d, want 3307", db.Port)
+	}
+	if db.User != "yes" {
+		t.Errorf("user = %q, want %q", db.User, "yes")
+	}
+	if c.ShutdownTimeout != "1e3" {
+		t.Errorf("shutdown timeout = %q, want %q", c.ShutdownTimeout, "1e3")
+	}
+
+	app, _ := c.Custom["app"].(map[string]any)
+	for key, want := range map[string]string{"code": "0123", "enabled": "no", "new": "1e3"} {
+		if got := app[key]; got != want {
+			t.Errorf("app.%s = %#v, want %q", key, got, want)
+		}
+	}
+}
+
+func TestExpandEnv(t *testing.T) {
+	t.Setenv("TEST_DB_PORT", "3307")
+	t.Setenv("TEST_DB_PASSWORD", "a#b: *c\nd")
+	t.Setenv("TEST_APP_NAME", "&name")
+
+	data := []byte("bind: 127.0.0.1:8000\n" +
+		"# ${TEST_UNSET_IN_COMMENT}\n" +
+		"database:\n  default:\n    driver: mysql\n    port: ${TEST_DB_PORT}\n    password: ${TEST_DB_PASSWORD}\n    user: ${TEST_DB_USER:-root}\n" +
+		"app:\n  name: ${TEST_APP_NAME}\n  port: \"${TEST_DB_PORT}\"\n  url: http://${TEST_APP_HOST:-localhost}:${TEST_DB_PORT}/\n")
+	c, err := loadConfig(&configReader{name: "config.yaml", data: data}, "config.yaml", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(c.decodeErrs) > 0 {
+		t.Fatal(c.decodeErrs)
+	}
+
+	db := c.Database["default"]
+	if db.Port != 3307 {
+		t.Errorf("port = %d, want 3307", db.Port)
+	}
+	if db.Password != "a#b: *c\nd" {
+		t.Errorf("password = %q, want %q", db.Password, "a#b: *c\nd")
+	}
+	if db.User != "root" {
+		t.Errorf("user = %q, want %q", db.User, "root")
+	}
+
+	app, _ := c.Custom["app"].(map[string]any)
+	for key, want := range map[string]string{"name": "&name", "port": "3307", "url": "http://localhost:3307/"} {
+		if got := app[key]; got != want {
+			t.Errorf("app.%s = %#v, want %q", key, got, want)
+		}
+	}
+}
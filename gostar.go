@@ -1,8 +1,18 @@
 package gostar
 
 import (
+	"context"
+	"errors"
 	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
 
+	"github.com/shi-yunsheng/gostar/date"
 	"github.com/shi-yunsheng/gostar/logger"
 	"github.com/shi-yunsheng/gostar/model"
 	"github.com/shi-yunsheng/gostar/router"
@@ -11,45 +21,189 @@ import (
 )
 
 var (
-	instance *goStar
+	// 默认GoStar实例，即第一个创建成功的实例
+	instance     *goStar
+	instanceLock sync.Mutex
 )
 
+// 默认优雅关闭的最长等待时间
+const defaultShutdownTimeout = 30 * time.Second
+
+// 启用健康检查时，默认在就绪检查返回失败后等待负载均衡摘除实例的时间
+const defaultShutdownDelay = 5 * time.Second
+
+// 设置了GOSTAR_ROUTES_FILE时，Run和RunWithGracefulShutdown输出路由表后返回该错误，不会启动服务
+var ErrRoutesDumped = errors.New("gostar: routes dumped")
+
+// GoStar应用实例，New等函数返回的类型，用于在其他包中声明变量或字段
+type App = goStar
+
 type goStar struct {
 	version string
-	config  *config
+	config  atomic.Pointer[config]
 	server  *http.Server
 	router  *router.Router
+	// 配置文件名
+	configName string
+	// 配置读取器
+	configReader *configReader
+	// 配置环境
+	profile string
+	// 配置变更订阅者
+	configListeners     []configListener
+	configListenersLock sync.RWMutex
+	// 停止监听配置文件
+	stopWatch     chan struct{}
+	stopWatchLock sync.Mutex
+	// HTTP重定向到HTTPS的服务器
+	redirectServer *http.Server
+	// 管理地址的HTTP服务器，只提供管理路由
+	adminServer *http.Server
+	// 所有监听器，重启时传递给新进程
+	listeners     []*bindListener
+	listenersLock sync.Mutex
+	// 日志器，默认实例使用logger包的默认日志器
+	logger *logger.Logger
+	// 数据库和Redis连接注册表，默认实例使用model包的默认注册表
+	registry *model.Registry
+	// websocket连接组，默认实例使用handler包的默认连接组
+	websockets *handler.WebsocketGroup
+	// 是否为默认实例
+	isDefault bool
+	// 生命周期钩子
+	startHooks    []lifecycleHook
+	readyHooks    []lifecycleHook
+	shutdownHooks []lifecycleHook
+	hooksLock     sync.Mutex
+	// 用户注册的就绪检查
+	healthChecks     []namedHealthCheck
+	healthChecksLock sync.RWMutex
+	// 是否已开始优雅关闭
+	shuttingDown atomic.Bool
+	// 是否只输出路由表（gostar routes），该模式下不连接数据库和Redis，也不监听配置文件
+	dumpingRoutes bool
 }
 
-// 新建GoStar实例
+// 新建GoStar实例，配置或初始化失败时panic
 func New(configName ...string) *goStar {
-	instance = &goStar{
-		version: "1.0.49-beta",
-		config:  getConfig(configName...),
-		router:  router.NewRouter(),
+	return NewWithProfile("", configName...)
+}
+
+// 新建GoStar实例，配置或初始化失败时返回错误
+// 配置校验失败时返回ConfigErrors，包含所有错误的配置路径和行号
+func NewE(configName ...string) (*goStar, error) {
+	return NewWithProfileE("", configName...)
+}
+
+// 新建指定环境的GoStar实例，会在基础配置之上深度合并对应环境的配置文件，如：config.prod.yaml
+// profile为空时使用环境变量GOSTAR_PROFILE，配置或初始化失败时panic
+func NewWithProfile(profile string, configName ...string) *goStar {
+	g, err := NewWithProfileE(profile, configName...)
+	if err != nil {
+		panic(err)
 	}
-	instance.initGoStar()
-	return instance
+	return g
+}
+
+// 新建指定环境的GoStar实例，配置或初始化失败时返回错误
+// 第一个创建成功的实例为默认实例，使用各个包的默认日志器、连接注册表和websocket连接组，以兼容包级别函数；
+// 之后创建的实例拥有独立的路由、配置、日志器、数据库和Redis连接，可在同一进程中监听不同地址
+func NewWithProfileE(profile string, configName ...string) (*goStar, error) {
+	return newGoStar(&configReader{}, profile, getConfigName(configName...), false)
+}
+
+// 新建GoStar实例，从configReader读取配置，isolated为true时不会成为默认实例
+func newGoStar(reader *configReader, profile string, configName string, isolated bool) (*goStar, error) {
+	instanceLock.Lock()
+	defer instanceLock.Unlock()
+
+	g := &goStar{
+		version:      "1.0.49-beta",
+		router:       router.NewRouter(),
+		configName:   configName,
+		configReader: reader,
+		profile:      getProfile(profile),
+		isDefault:    instance == nil && !isolated,
+		// gostar routes命令只需要路由表，不初始化数据库、Redis等依赖
+		dumpingRoutes: os.Getenv(envRoutesFile) != "",
+	}
+	if g.isDefault {
+		g.logger = logger.Default()
+		g.registry = model.DefaultRegistry()
+		g.websockets = handler.DefaultWebsocketGroup()
+	} else {
+		g.logger = logger.New()
+		g.registry = model.NewRegistry()
+		g.websockets = handler.NewWebsocketGroup()
+	}
+
+	config, err := getConfig(g.configReader, g.configName, g.profile)
+	if err != nil {
+		return nil, err
+	}
+	if err := config.validate(); err != nil {
+		return nil, err
+	}
+	g.config.Store(config)
+
+	if err := g.initGoStar(); err != nil {
+		return nil, err
+	}
+	if g.isDefault {
+		instance = g
+	}
+	return g, nil
 }
 
 // 初始化GoStar
-func (g *goStar) initGoStar() {
+func (g *goStar) initGoStar() error {
+	config := g.config.Load()
 	// 如果调试模式已开启，则开启各个组件的调试模式
-	if g.config.Debug {
-		handler.EnableDebug()
-		model.EnableDebug()
+	if config.Debug {
+		g.registry.EnableDebug()
+		// 处理器的全局调试模式只由默认实例开启，其他实例通过请求上下文区分
+		if g.isDefault {
+			handler.EnableDebug()
+		}
 	}
 
-	g.initDate()
+	if err := g.initDate(); err != nil {
+		return err
+	}
 	g.initLog()
-	model.InitDB(g.config.Database)
-	model.InitRedis(g.config.Redis)
-	// 使用默认路由中间件
+	g.initLang()
+	if !g.dumpingRoutes {
+		if err := g.registry.InitDBE(config.Database); err != nil {
+			return err
+		}
+		if err := g.registry.InitRedisE(config.Redis); err != nil {
+			// 关闭已打开的数据库连接，避免初始化失败的实例泄漏连接池
+			return errors.Join(err, g.registry.CloseDB())
+		}
+	}
+	// 使用默认路由中间件，跨域来源从当前配置读取，以支持热加载
 	g.router.UseMiddleware(
+		g.contextMiddleware,
 		middleware.ErrorMiddleware,
 		middleware.LogMiddleware,
-		middleware.CORSMiddleware(g.config.AllowedOrigins),
+		middleware.CORSMiddlewareFunc(func() []string {
+			return g.config.Load().AllowedOrigins
+		}),
+		middleware.BodyLimitMiddlewareFunc(func() int64 {
+			return g.config.Load().Server.maxBodySize()
+		}),
 	)
+	// 开启配置热加载
+	if config.HotReload && !g.dumpingRoutes {
+		g.watchConfig()
+	}
+
+	return nil
+}
+
+// 是否只输出路由表，由gostar routes命令启动时为true，此时数据库和Redis未连接，应跳过迁移等有副作用的操作
+func (g *goStar) DumpingRoutes() bool {
+	return g.dumpingRoutes
 }
 
 // 返回GoStar的版本
@@ -57,25 +211,322 @@ func (g *goStar) Version() string {
 	return g.version
 }
 
+// 获取处理请求的http.Handler，与服务器使用相同的路由、全局中间件和健康检查，不区分普通地址和管理地址
+// 可用于httptest或挂载到其他HTTP服务器
+func (g *goStar) Handler() http.Handler {
+	g.router.MountGroups()
+	return g.newHandler("")
+}
+
+// 创建HTTP服务器，listener为监听地址类型，用于区分普通地址和管理地址
+func (g *goStar) newServer(listener string) (*http.Server, error) {
+	config := g.config.Load()
+	addr := config.Bind.first()
+	if listener == handler.AdminListener {
+		addr = config.AdminBind.first()
+	}
+
+	server := &http.Server{
+		Addr:    addr,
+		Handler: g.newHandler(listener),
+	}
+	config.Server.apply(server)
+	// 如果启用了TLS，则构建TLS配置
+	if config.TLS.Enable {
+		tlsConf, err := config.TLS.build()
+		if err != nil {
+			return nil, err
+		}
+		server.TLSConfig = tlsConf
+	}
+
+	return server, nil
+}
+
+// 根据配置选择HTTP或HTTPS服务
+func (g *goStar) serve(server *http.Server, ln *bindListener) error {
+	config := g.config.Load()
+	if config.TLS.Enable {
+		g.logger.I("GoStar is running on https://" + ln.address)
+		return server.ServeTLS(ln, config.TLS.CertFile, config.TLS.KeyFile)
+	}
+
+	g.logger.I("GoStar is running on " + ln.address)
+	return server.Serve(ln)
+}
+
+// 启动GoStar：执行启动钩子，监听所有地址并开始服务，然后执行就绪钩子
+// 返回的通道会在任意服务结束时收到服务的返回值
+func (g *goStar) start() (<-chan error, error) {
+	g.router.MountGroups()
+	// gostar routes命令只需要路由表，输出后返回ErrRoutesDumped，不启动服务
+	if g.dumpingRoutes {
+		if err := g.dumpRoutes(); err != nil {
+			return nil, err
+		}
+		return nil, ErrRoutesDumped
+	}
+	config := g.config.Load()
+	server, err := g.newServer(handler.PublicListener)
+	if err != nil {
+		return nil, err
+	}
+	g.server = server
+	if len(config.AdminBind) > 0 {
+		adminServer, err := g.newServer(handler.AdminListener)
+		if err != nil {
+			return nil, err
+		}
+		g.adminServer = adminServer
+	}
+
+	if err := g.runHooks("start", g.startHooks); err != nil {
+		return nil, err
+	}
+
+	listeners, err := g.listenAll(handler.PublicListener, config.Bind)
+	if err != nil {
+		return nil, err
+	}
+	adminListeners, err := g.listenAll(handler.AdminListener, config.AdminBind)
+	if err != nil {
+		closeListeners(listeners)
+		return nil, err
+	}
+
+	errChan := make(chan error, len(listeners)+len(adminListeners))
+	for _, ln := range listeners {
+		go func(ln *bindListener) {
+			errChan <- g.serve(server, ln)
+		}(ln)
+	}
+	for _, ln := range adminListeners {
+		go func(ln *bindListener) {
+			errChan <- g.serve(g.adminServer, ln)
+		}(ln)
+	}
+	if config.TLS.Enable {
+		if err := g.startRedirectServer(); err != nil {
+			g.closeServers()
+			return nil, err
+		}
+	}
+	closeUnusedInheritedListeners()
+
+	if err := g.runHooks("ready", g.readyHooks); err != nil {
+		g.closeServers()
+		return nil, err
+	}
+	// 由旧进程重启而来时，通知旧进程已就绪
+	notifyRestartReady()
+
+	return errChan, nil
+}
+
 // 运行GoStar
 func (g *goStar) Run() error {
-	logger.I("GoStar is running on " + g.config.Bind)
+	errChan, err := g.start()
+	if err != nil {
+		return err
+	}
+
+	return <-errChan
+}
+
+// 运行GoStar，收到SIGINT或SIGTERM信号后优雅关闭
+// 配置restart.enable为true时，收到SIGHUP信号后启动新的可执行文件并传递监听器，新进程就绪后当前进程优雅关闭
+func (g *goStar) RunWithGracefulShutdown() error {
+	errChan, err := g.start()
+	if err != nil {
+		return err
+	}
+
+	signals := []os.Signal{syscall.SIGINT, syscall.SIGTERM}
+	restartEnabled := g.config.Load().Restart.Enable
+	if restartEnabled {
+		signals = append(signals, syscall.SIGHUP)
+	}
+
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, signals...)
+	defer signal.Stop(quit)
+
+	for {
+		select {
+		case err := <-errChan:
+			if errors.Is(err, http.ErrServerClosed) {
+				return nil
+			}
+			return err
+		case sig := <-quit:
+			if sig == syscall.SIGHUP && restartEnabled {
+				g.logger.I("Received signal %s, restarting with zero downtime", sig)
+				if err := g.restart(); err != nil {
+					g.logger.E("Restart failed, keeping the current process: %v", err)
+					continue
+				}
+				g.logger.I("New process is ready, shutting down the current process")
+			} else {
+				g.logger.I("Received signal %s, shutting down gracefully", sig)
+			}
+		}
+
+		return g.Shutdown()
+	}
+}
+
+// 获取优雅关闭的最长等待时间
+func (g *goStar) getShutdownTimeout() time.Duration {
+	shutdownTimeout := g.config.Load().ShutdownTimeout
+	if strings.TrimSpace(shutdownTimeout) == "" {
+		return defaultShutdownTimeout
+	}
 
-	g.server = &http.Server{
-		Addr:    g.config.Bind,
-		Handler: g.router.GetMux(),
+	timeout, err := date.ParseTimeDuration(shutdownTimeout)
+	if err != nil || timeout <= 0 {
+		g.logger.W("Invalid shutdown_timeout %q, using default %v", shutdownTimeout, defaultShutdownTimeout)
+		return defaultShutdownTimeout
 	}
 
-	return g.server.ListenAndServe()
+	return timeout
+}
+
+// 获取就绪检查返回失败后、停止接收请求前的等待时间，未配置时启用健康检查默认5s，否则不等待
+func (g *goStar) getShutdownDelay() time.Duration {
+	config := g.config.Load()
+	if strings.TrimSpace(config.ShutdownDelay) == "" {
+		if config.Health.Enable {
+			return defaultShutdownDelay
+		}
+		return 0
+	}
+
+	delay, err := date.ParseTimeDuration(config.ShutdownDelay)
+	if err != nil || delay < 0 {
+		g.logger.W("Invalid shutdown_delay %q, using default %v", config.ShutdownDelay, defaultShutdownDelay)
+		return defaultShutdownDelay
+	}
+
+	return delay
+}
+
+// 优雅关闭GoStar
+// 就绪检查先返回失败，等待shutdown_delay后停止接收新请求并等待处理中的请求和WebSocket连接结束，
+// 然后逆序执行关闭钩子，最后依次关闭数据库、Redis和日志
+func (g *goStar) Shutdown() error {
+	// 就绪检查立即返回失败，使负载均衡停止转发新请求
+	g.shuttingDown.Store(true)
+	g.stopWatchConfig()
+
+	// 负载均衡摘除实例前仍可能转发新请求，等待期间继续正常处理
+	if delay := g.getShutdownDelay(); delay > 0 {
+		g.logger.I("Waiting %v for load balancers to stop sending requests", delay)
+		time.Sleep(delay)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), g.getShutdownTimeout())
+	defer cancel()
+
+	var errs []error
+	for _, server := range g.getServers() {
+		if err := server.Shutdown(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if err := g.websockets.Shutdown(ctx); err != nil {
+		errs = append(errs, err)
+	}
+	if err := g.runShutdownHooks(); err != nil {
+		errs = append(errs, err)
+	}
+	if err := g.registry.CloseDB(); err != nil {
+		errs = append(errs, err)
+	}
+	if err := g.registry.CloseRedis(); err != nil {
+		errs = append(errs, err)
+	}
+
+	if len(errs) > 0 {
+		g.logger.E("GoStar shutdown with errors: %v", errors.Join(errs...))
+	} else {
+		g.logger.I("GoStar has been shut down")
+	}
+	g.logger.Close()
+
+	return errors.Join(errs...)
 }
 
 // 关闭GoStar
 func (g *goStar) Close() error {
-	logger.Close()
-	return g.server.Close()
+	g.stopWatchConfig()
+	g.logger.Close()
+	return g.closeServers()
+}
+
+// 获取所有已创建的HTTP服务器
+func (g *goStar) getServers() []*http.Server {
+	var servers []*http.Server
+	for _, server := range []*http.Server{g.redirectServer, g.server, g.adminServer} {
+		if server != nil {
+			servers = append(servers, server)
+		}
+	}
+	return servers
 }
 
-// 获取GoStar上下文
+// 立即关闭所有HTTP服务器
+func (g *goStar) closeServers() error {
+	var errs []error
+	for _, server := range g.getServers() {
+		if err := server.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// 获取GoStar上下文，返回默认实例
 func GetContext() *goStar {
 	return instance
 }
+
+// 获取处理当前请求的GoStar实例，请求不是由GoStar实例处理时返回默认实例
+func FromRequest(r handler.Request) *goStar {
+	if g, ok := r.GetApp().(*goStar); ok && g != nil {
+		return g
+	}
+	return instance
+}
+
+// 请求上下文中间件，将当前实例、日志器、调试模式和websocket连接组写入请求上下文
+func (g *goStar) contextMiddleware(next handler.Handler) handler.Handler {
+	return func(w *handler.Response, r handler.Request) any {
+		r.SetApp(g)
+		r.SetLogger(g.logger)
+		r.SetDebug(g.config.Load().Debug)
+		r.SetFallbackLang(g.config.Load().Lang)
+		r.SetWebsocketGroup(g.websockets)
+
+		return next(w, r)
+	}
+}
+
+// 获取当前实例的日志器
+func (g *goStar) Logger() *logger.Logger {
+	return g.logger
+}
+
+// 获取当前实例的数据库和Redis连接注册表
+func (g *goStar) Registry() *model.Registry {
+	return g.registry
+}
+
+// 获取当前实例的数据库连接，不传名称时获取默认连接
+func (g *goStar) DB(name ...string) *model.DBClient {
+	return g.registry.GetDB(name...)
+}
+
+// 获取当前实例的Redis连接，不传名称时获取默认连接
+func (g *goStar) Redis(name ...string) *model.RedisClient {
+	return g.registry.GetRedis(name...)
+}
@@ -0,0 +1,226 @@
+package gostartest
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// 测试请求，通过链式调用设置请求头、查询参数和请求体
+type Request struct {
+	app     *App
+	method  string
+	path    string
+	host    string
+	header  http.Header
+	query   url.Values
+	cookies []*http.Cookie
+	body    []byte
+}
+
+// 新建测试请求，path可以包含查询参数，如：/users?page=1
+func (a *App) NewRequest(method string, path string) *Request {
+	return &Request{
+		app:    a,
+		method: method,
+		path:   path,
+		header: make(http.Header),
+		query:  make(url.Values),
+	}
+}
+
+// 新建GET请求
+func (a *App) GET(path string) *Request {
+	return a.NewRequest(http.MethodGet, path)
+}
+
+// 新建POST请求
+func (a *App) POST(path string) *Request {
+	return a.NewRequest(http.MethodPost, path)
+}
+
+// 新建PUT请求
+func (a *App) PUT(path string) *Request {
+	return a.NewRequest(http.MethodPut, path)
+}
+
+// 新建PATCH请求
+func (a *App) PATCH(path string) *Request {
+	return a.NewRequest(http.MethodPatch, path)
+}
+
+// 新建DELETE请求
+func (a *App) DELETE(path string) *Request {
+	return a.NewRequest(http.MethodDelete, path)
+}
+
+// 新建HEAD请求
+func (a *App) HEAD(path string) *Request {
+	return a.NewRequest(http.MethodHead, path)
+}
+
+// 新建OPTIONS请求
+func (a *App) OPTIONS(path string) *Request {
+	return a.NewRequest(http.MethodOptions, path)
+}
+
+// 设置请求头
+func (r *Request) WithHeader(key string, value string) *Request {
+	r.header.Set(key, value)
+	return r
+}
+
+// 设置请求域名，默认为example.com
+func (r *Request) WithHost(host string) *Request {
+	r.host = host
+	return r
+}
+
+// 添加查询参数
+func (r *Request) WithQuery(key string, value string) *Request {
+	r.query.Add(key, value)
+	return r
+}
+
+// 添加Cookie
+func (r *Request) WithCookie(cookie *http.Cookie) *Request {
+	r.cookies = append(r.cookies, cookie)
+	return r
+}
+
+// 设置请求体和Content-Type
+func (r *Request) WithBody(contentType string, body []byte) *Request {
+	r.header.Set("Content-Type", contentType)
+	r.body = body
+	return r
+}
+
+// 设置JSON请求体，编码失败时panic
+func (r *Request) WithJSON(value any) *Request {
+	body, err := json.Marshal(value)
+	if err != nil {
+		panic("gostartest: encode json body failed: " + err.Error())
+	}
+	return r.WithBody("application/json", body)
+}
+
+// 设置表单请求体
+func (r *Request) WithForm(form url.Values) *Request {
+	return r.WithBody("application/x-www-form-urlencoded", []byte(form.Encode()))
+}
+
+// 发送请求，经过与服务器相同的全局中间件和路由处理，返回响应记录
+func (r *Request) Do() *httptest.ResponseRecorder {
+	target := r.path
+	if len(r.query) > 0 {
+		separator := "?"
+		if strings.Contains(target, "?") {
+			separator = "&"
+		}
+		target += separator + r.query.Encode()
+	}
+
+	var body io.Reader
+	if r.body != nil {
+		body = bytes.NewReader(r.body)
+	}
+	req := httptest.NewRequest(r.method, target, body)
+	if r.host != "" {
+		req.Host = r.host
+	}
+	for key, values := range r.header {
+		req.Header[key] = values
+	}
+	for _, cookie := range r.cookies {
+		req.AddCookie(cookie)
+	}
+
+	recorder := httptest.NewRecorder()
+	r.app.Handler().ServeHTTP(recorder, req)
+	return recorder
+}
+
+// 发送请求并返回用于断言的响应，断言失败时调用t.Errorf，不会中断测试
+func (r *Request) Expect(t testing.TB) *Response {
+	t.Helper()
+	return &Response{t: t, Recorder: r.Do()}
+}
+
+// 测试响应，通过链式调用进行断言
+type Response struct {
+	t testing.TB
+	// 原始响应记录
+	Recorder *httptest.ResponseRecorder
+}
+
+// 断言状态码
+func (r *Response) Status(code int) *Response {
+	r.t.Helper()
+	if r.Recorder.Code != code {
+		r.t.Errorf("expected status %d, got %d, body: %s", code, r.Recorder.Code, r.Recorder.Body.String())
+	}
+	return r
+}
+
+// 断言响应头
+func (r *Response) Header(key string, value string) *Response {
+	r.t.Helper()
+	if actual := r.Recorder.Header().Get(key); actual != value {
+		r.t.Errorf("expected header %s to be %q, got %q", key, value, actual)
+	}
+	return r
+}
+
+// 断言响应体与expected完全相同
+func (r *Response) Body(expected string) *Response {
+	r.t.Helper()
+	if actual := r.Recorder.Body.String(); actual != expected {
+		r.t.Errorf("expected body %q, got %q", expected, actual)
+	}
+	return r
+}
+
+// 断言响应体包含substr
+func (r *Response) BodyContains(substr string) *Response {
+	r.t.Helper()
+	if actual := r.Recorder.Body.String(); !strings.Contains(actual, substr) {
+		r.t.Errorf("expected body to contain %q, got %q", substr, actual)
+	}
+	return r
+}
+
+// 断言JSON响应体与expected等价，expected可以是map、切片、结构体等任何可以编码为JSON的值，不比较字段顺序
+func (r *Response) JSON(expected any) *Response {
+	r.t.Helper()
+	expectedData, err := json.Marshal(expected)
+	if err != nil {
+		r.t.Errorf("encode expected json failed: %v", err)
+		return r
+	}
+
+	var expectedValue, actualValue any
+	json.Unmarshal(expectedData, &expectedValue)
+	if err := json.Unmarshal(r.Recorder.Body.Bytes(), &actualValue); err != nil {
+		r.t.Errorf("decode response json failed: %v, body: %s", err, r.Recorder.Body.String())
+		return r
+	}
+	if !reflect.DeepEqual(expectedValue, actualValue) {
+		r.t.Errorf("expected json %s, got %s", expectedData, strings.TrimSpace(r.Recorder.Body.String()))
+	}
+	return r
+}
+
+// 将JSON响应体解析到v，用于进一步断言
+func (r *Response) Decode(v any) *Response {
+	r.t.Helper()
+	if err := json.Unmarshal(r.Recorder.Body.Bytes(), v); err != nil {
+		r.t.Errorf("decode response json failed: %v, body: %s", err, r.Recorder.Body.String())
+	}
+	return r
+}
@@ -0,0 +1,152 @@
+// 提供测试gostar应用的工具：在进程内创建应用、使用内存SQLite和Redis替身、通过httptest发送请求并断言响应
+package gostartest
+
+import (
+	"bytes"
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/shi-yunsheng/gostar"
+
+	"gopkg.in/yaml.v3"
+)
+
+// 测试应用的配置
+type Config struct {
+	// 框架配置和自定义配置，结构与config.yaml相同，如：{"debug": true, "upload": {"max_size": "1MB"}}
+	// 其中database和redis下的连接会被替换为内存SQLite和Redis替身，保留table_prefix和prefix
+	Config map[string]any
+	// 额外使用内存SQLite的数据库连接名，如：[]string{"default"}
+	Databases []string
+	// 额外使用Redis替身的Redis连接名，如：[]string{"default"}
+	Redis []string
+}
+
+// 测试应用，内嵌gostar应用，可以直接调用UseRouter、UseMiddleware、DB、Redis等方法
+type App struct {
+	*gostar.App
+	t     testing.TB
+	redis *RedisServer
+	// 第一次发送请求时创建的http.Handler
+	handler     http.Handler
+	handlerOnce sync.Once
+}
+
+// 内存数据库编号，保证每个测试应用使用独立的数据库
+var databaseSeq atomic.Int64
+
+// 创建测试应用，不会读写配置文件，也不会监听端口，测试结束时自动关闭应用、数据库和Redis替身
+// 测试应用不会成为默认实例，拥有独立的日志器、数据库和Redis连接，处理器中应通过gostar.FromRequest获取当前实例
+func New(t testing.TB, config ...Config) *App {
+	t.Helper()
+
+	var c Config
+	if len(config) > 0 {
+		c = config[0]
+	}
+	app := &App{t: t}
+
+	allConfig := maps.Clone(c.Config)
+	if allConfig == nil {
+		allConfig = make(map[string]any)
+	}
+	// 测试应用不会监听端口，绑定地址只用于通过校验
+	if _, ok := allConfig["bind"]; !ok {
+		allConfig["bind"] = "127.0.0.1:0"
+	}
+	allConfig["database"] = memoryDatabases(allConfig["database"], c.Databases)
+
+	redisNames := append(configNames(allConfig["redis"]), c.Redis...)
+	if len(redisNames) > 0 {
+		server, err := NewRedisServer()
+		if err != nil {
+			t.Fatalf("gostartest: start redis server failed: %v", err)
+		}
+		t.Cleanup(func() { server.Close() })
+		app.redis = server
+		allConfig["redis"] = standInRedis(allConfig["redis"], redisNames, server)
+	}
+
+	data, err := yaml.Marshal(allConfig)
+	if err != nil {
+		t.Fatalf("gostartest: encode config failed: %v", err)
+	}
+	g, err := gostar.NewIsolatedFromReader(bytes.NewReader(data), "gostartest.yaml")
+	if err != nil {
+		t.Fatalf("gostartest: create app failed: %v", err)
+	}
+	app.App = g
+	t.Cleanup(func() {
+		registry := g.Registry()
+		registry.CloseDB()
+		registry.CloseRedis()
+		g.Close()
+	})
+
+	return app
+}
+
+// 获取处理请求的http.Handler，第一次调用时创建，之后复用。路由组在第一次调用时挂载，之后创建的路由组不会生效
+func (a *App) Handler() http.Handler {
+	a.handlerOnce.Do(func() {
+		a.handler = a.App.Handler()
+	})
+	return a.handler
+}
+
+// 获取Redis替身，没有配置Redis时返回nil
+func (a *App) RedisServer() *RedisServer {
+	return a.redis
+}
+
+// 将数据库配置替换为内存SQLite，保留表前缀
+func memoryDatabases(value any, extra []string) map[string]any {
+	databases := make(map[string]any)
+	current, _ := value.(map[string]any)
+	seq := databaseSeq.Add(1)
+
+	for _, name := range append(configNames(value), extra...) {
+		database := map[string]any{
+			"driver": "sqlite",
+			"dsn":    fmt.Sprintf("file:gostartest_%d_%s?mode=memory&cache=shared", seq, name),
+		}
+		if config, ok := current[name].(map[string]any); ok && config["table_prefix"] != nil {
+			database["table_prefix"] = config["table_prefix"]
+		}
+		databases[name] = database
+	}
+	return databases
+}
+
+// 将Redis配置替换为Redis替身，每个连接使用不同的数据库编号，保留键前缀
+func standInRedis(value any, names []string, server *RedisServer) map[string]any {
+	redis := make(map[string]any)
+	current, _ := value.(map[string]any)
+
+	for i, name := range slices.Compact(slices.Sorted(slices.Values(names))) {
+		config := map[string]any{
+			"host": "127.0.0.1",
+			"port": server.Port(),
+			"db":   i,
+		}
+		if existing, ok := current[name].(map[string]any); ok && existing["prefix"] != nil {
+			config["prefix"] = existing["prefix"]
+		}
+		redis[name] = config
+	}
+	return redis
+}
+
+// 获取配置中的连接名
+func configNames(value any) []string {
+	connections, ok := value.(map[string]any)
+	if !ok {
+		return nil
+	}
+	return slices.Sorted(maps.Keys(connections))
+}
@@ -0,0 +1,179 @@
+package gostartest
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/shi-yunsheng/gostar"
+	"github.com/shi-yunsheng/gostar/logger"
+	"github.com/shi-yunsheng/gostar/model"
+	"github.com/shi-yunsheng/gostar/router"
+	"github.com/shi-yunsheng/gostar/router/handler"
+)
+
+// 测试路由
+func testRoutes() []router.Route {
+	return []router.Route{
+		{Path: "/users/{id:int}", Method: router.GET, Handler: func(w *handler.Response, r handler.Request) any {
+			return map[string]any{"id": r.GetParam("id"), "tab": r.GetQuery("tab", "")}
+		}},
+		{Path: "/users", Method: router.POST, Handler: func(w *handler.Response, r handler.Request) any {
+			body, err := r.GetAllBody()
+			if err != nil {
+				return err
+			}
+			w.SetHeader("X-Created", "1")
+			w.WriteHeader(http.StatusCreated)
+			w.Json(body)
+			return nil
+		}},
+		{Path: "/forms", Method: router.POST, Handler: func(w *handler.Response, r handler.Request) any {
+			return r.FormValue("name")
+		}},
+		{Path: "/tenant", Host: "{tenant}.example.com", Handler: func(w *handler.Response, r handler.Request) any {
+			return r.GetParam("tenant")
+		}},
+		{Path: "/app", Handler: func(w *handler.Response, r handler.Request) any {
+			return gostar.FromRequest(r).Version()
+		}},
+	}
+}
+
+func TestRequest(t *testing.T) {
+	app := New(t)
+	app.UseRouter(testRoutes())
+
+	app.GET("/users/7").WithQuery("tab", "info").Expect(t).Status(http.StatusOK).JSON(map[string]any{"id": 7, "tab": "info"})
+	app.POST("/users").WithJSON(map[string]any{"name": "bob"}).Expect(t).Status(http.StatusCreated).Header("X-Created", "1").JSON(map[string]any{"name": "bob"})
+	app.POST("/forms").WithForm(url.Values{"name": {"amy"}}).Expect(t).Status(http.StatusOK).BodyContains("amy")
+	app.DELETE("/users").Expect(t).Status(http.StatusMethodNotAllowed).Header("Allow", "OPTIONS, POST")
+	app.GET("/tenant").WithHost("acme.example.com").Expect(t).Status(http.StatusOK).BodyContains("acme")
+	app.GET("/tenant").Expect(t).Status(http.StatusNotFound)
+	app.GET("/app").Expect(t).Status(http.StatusOK).BodyContains(app.Version())
+
+	// 第一次请求之后使用的路由同样生效
+	app.UseRouter([]router.Route{{Path: "/late", Handler: func(w *handler.Response, r handler.Request) any {
+		return "late"
+	}}})
+	app.GET("/late").Expect(t).Status(http.StatusOK).BodyContains("late")
+}
+
+func TestIsolation(t *testing.T) {
+	before := gostar.GetContext()
+	var first *App
+	t.Run("first", func(t *testing.T) {
+		first = New(t)
+		first.UseRouter(testRoutes())
+		first.GET("/users/1").Expect(t).Status(http.StatusOK)
+	})
+	if gostar.GetContext() != before {
+		t.Fatal("test app changed the default instance")
+	}
+	if first.Logger() == logger.Default() {
+		t.Fatal("test app uses the default logger")
+	}
+
+	// 之前的测试应用关闭后，新的测试应用不受影响
+	second := New(t, Config{Databases: []string{"default"}, Redis: []string{"default"}})
+	second.UseRouter(testRoutes())
+	second.GET("/users/2").Expect(t).Status(http.StatusOK).JSON(map[string]any{"id": 2, "tab": ""})
+	if err := second.DB().Ping(context.Background()); err != nil {
+		t.Fatalf("ping database: %v", err)
+	}
+	if err := second.Redis().Set("key", "value", ""); err != nil {
+		t.Fatalf("redis set: %v", err)
+	}
+	if value, err := second.Redis().Get("key"); err != nil || value != "value" {
+		t.Fatalf("redis get = %q, %v, want %q", value, err, "value")
+	}
+}
+
+// 测试模型
+type testItem struct {
+	ID   int64  `gorm:"primarykey" json:"id"`
+	Name string `json:"name"`
+}
+
+func TestModel(t *testing.T) {
+	app := New(t, Config{Databases: []string{"default"}})
+	if err := app.DB().AutoMigrate(&testItem{}); err != nil {
+		t.Fatalf("auto migrate: %v", err)
+	}
+	app.UseRouter([]router.Route{
+		{Path: "/items", Method: router.POST, Handler: func(w *handler.Response, r handler.Request) any {
+			body, err := r.GetAllBody()
+			if err != nil {
+				return err
+			}
+			return model.For[testItem](gostar.FromRequest(r).DB()).Insert(body)
+		}},
+		{Path: "/items", Method: router.GET, Handler: func(w *handler.Response, r handler.Request) any {
+			items, err := model.For[testItem](gostar.FromRequest(r).DB()).Query(map[string]any{"name": []any{r.GetQuery("name")}})
+			if err != nil {
+				return err
+			}
+			return items
+		}},
+	})
+
+	app.POST("/items").WithJSON(map[string]any{"id": 1, "name": "apple"}).Expect(t).Status(http.StatusOK)
+	app.POST("/items").WithJSON(map[string]any{"id": 2, "name": "pear"}).Expect(t).Status(http.StatusOK)
+	app.GET("/items").WithQuery("name", "pear").Expect(t).Status(http.StatusOK).JSON([]map[string]any{{"id": 2, "name": "pear"}})
+
+	repo := model.For[testItem](app.DB())
+	count, err := repo.Count(nil)
+	if err != nil || count != 2 {
+		t.Fatalf("count = %d, %v, want 2", count, err)
+	}
+	item, err := repo.First(map[string]any{"id": []any{1}})
+	if err != nil || item.Name != "apple" {
+		t.Fatalf("first = %+v, %v, want apple", item, err)
+	}
+
+	// 每个测试应用使用独立的数据库
+	other := New(t, Config{Databases: []string{"default"}})
+	if err := other.DB().AutoMigrate(&testItem{}); err != nil {
+		t.Fatalf("auto migrate: %v", err)
+	}
+	if count, err := model.For[testItem](other.DB()).Count(nil); err != nil || count != 0 {
+		t.Fatalf("other app count = %d, %v, want 0", count, err)
+	}
+}
+
+func TestGetFileBodyLimit(t *testing.T) {
+	app := New(t, Config{Config: map[string]any{"server": map[string]any{"max_body_size": "1KB"}}})
+	app.UseRouter([]router.Route{{Path: "/upload", Method: router.POST, Handler: func(w *handler.Response, r handler.Request) any {
+		return len(r.GetFile("file", nil))
+	}}})
+
+	upload := func(size int) *httptest.ResponseRecorder {
+		var body bytes.Buffer
+		writer := multipart.NewWriter(&body)
+		part, err := writer.CreateFormFile("file", "a.txt")
+		if err != nil {
+			t.Fatal(err)
+		}
+		part.Write(bytes.Repeat([]byte("a"), size))
+		writer.Close()
+		// 隐藏请求体长度，使超出限制在读取时才被发现
+		req := httptest.NewRequest(http.MethodPost, "/upload", io.MultiReader(&body))
+		req.Header.Set("Content-Type", writer.FormDataContentType())
+		rec := httptest.NewRecorder()
+		app.Handler().ServeHTTP(rec, req)
+		return rec
+	}
+
+	if rec := upload(100); rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "1") {
+		t.Fatalf("small upload: status %d, body %q", rec.Code, rec.Body.String())
+	}
+	if rec := upload(4096); rec.Code != http.StatusRequestEntityTooLarge {
+		t.Fatalf("large upload: status %d, want %d", rec.Code, http.StatusRequestEntityTooLarge)
+	}
+}
@@ -0,0 +1,566 @@
+package gostartest
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 进程内的Redis替身，实现了RESP协议和常用的字符串、键、哈希命令，数据只保存在内存中
+// 用于测试中替代真实的Redis，go-redis客户端可以直接连接
+type RedisServer struct {
+	listener net.Listener
+	lock     sync.Mutex
+	// 数据库，键为数据库编号
+	dbs map[int]map[string]*redisEntry
+	// 所有连接，关闭时一起关闭
+	conns  map[net.Conn]struct{}
+	wg     sync.WaitGroup
+	closed bool
+}
+
+// Redis中的值
+type redisEntry struct {
+	// 字符串值
+	str string
+	// 哈希值，为nil时表示字符串
+	hash map[string]string
+	// 过期时间，零值表示永不过期
+	expireAt time.Time
+}
+
+// 是否已过期
+func (e *redisEntry) expired(now time.Time) bool {
+	return !e.expireAt.IsZero() && !now.Before(e.expireAt)
+}
+
+// 启动Redis替身，监听127.0.0.1的随机端口
+func NewRedisServer() (*RedisServer, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+	s := &RedisServer{
+		listener: listener,
+		dbs:      make(map[int]map[string]*redisEntry),
+		conns:    make(map[net.Conn]struct{}),
+	}
+	s.wg.Add(1)
+	go s.accept()
+	return s, nil
+}
+
+// 监听地址，如：127.0.0.1:6379
+func (s *RedisServer) Addr() string {
+	return s.listener.Addr().String()
+}
+
+// 监听端口
+func (s *RedisServer) Port() int {
+	return s.listener.Addr().(*net.TCPAddr).Port
+}
+
+// 清空所有数据库
+func (s *RedisServer) FlushAll() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.dbs = make(map[int]map[string]*redisEntry)
+}
+
+// 关闭Redis替身和所有连接
+func (s *RedisServer) Close() error {
+	s.lock.Lock()
+	if s.closed {
+		s.lock.Unlock()
+		return nil
+	}
+	s.closed = true
+	err := s.listener.Close()
+	for conn := range s.conns {
+		conn.Close()
+	}
+	s.lock.Unlock()
+
+	s.wg.Wait()
+	return err
+}
+
+// 接受连接
+func (s *RedisServer) accept() {
+	defer s.wg.Done()
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		s.lock.Lock()
+		if s.closed {
+			s.lock.Unlock()
+			conn.Close()
+			return
+		}
+		s.conns[conn] = struct{}{}
+		s.lock.Unlock()
+
+		s.wg.Add(1)
+		go s.serve(conn)
+	}
+}
+
+// 处理连接上的命令
+func (s *RedisServer) serve(conn net.Conn) {
+	defer s.wg.Done()
+	defer func() {
+		s.lock.Lock()
+		delete(s.conns, conn)
+		s.lock.Unlock()
+		conn.Close()
+	}()
+
+	reader := bufio.NewReader(conn)
+	writer := bufio.NewWriter(conn)
+	db := 0
+	for {
+		args, err := readCommand(reader)
+		if err != nil {
+			return
+		}
+		if len(args) == 0 {
+			continue
+		}
+
+		name := strings.ToUpper(args[0])
+		switch name {
+		case "QUIT":
+			writeReply(writer, simpleString("OK"))
+			writer.Flush()
+			return
+		case "SELECT":
+			if len(args) != 2 {
+				writeReply(writer, wrongArgs(name))
+				break
+			}
+			index, err := strconv.Atoi(args[1])
+			if err != nil || index < 0 {
+				writeReply(writer, errors.New("ERR DB index is out of range"))
+				break
+			}
+			db = index
+			writeReply(writer, simpleString("OK"))
+		default:
+			writeReply(writer, s.exec(db, name, args[1:]))
+		}
+		if err := writer.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+// 简单字符串回复，如：+OK
+type simpleString string
+
+// 参数数量错误
+func wrongArgs(name string) error {
+	return fmt.Errorf("ERR wrong number of arguments for '%s' command", strings.ToLower(name))
+}
+
+// 类型错误
+var errWrongType = errors.New("WRONGTYPE Operation against a key holding the wrong kind of value")
+
+// 执行命令，返回值为回复内容
+func (s *RedisServer) exec(index int, name string, args []string) any {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	db := s.dbs[index]
+	if db == nil {
+		db = make(map[string]*redisEntry)
+		s.dbs[index] = db
+	}
+	now := time.Now()
+	// 获取未过期的键
+	get := func(key string) *redisEntry {
+		entry, ok := db[key]
+		if !ok {
+			return nil
+		}
+		if entry.expired(now) {
+			delete(db, key)
+			return nil
+		}
+		return entry
+	}
+
+	switch name {
+	case "PING":
+		if len(args) > 0 {
+			return args[0]
+		}
+		return simpleString("PONG")
+	case "ECHO":
+		if len(args) != 1 {
+			return wrongArgs(name)
+		}
+		return args[0]
+	case "AUTH", "CLIENT":
+		return simpleString("OK")
+	case "GET":
+		if len(args) != 1 {
+			return wrongArgs(name)
+		}
+		entry := get(args[0])
+		if entry == nil {
+			return nil
+		}
+		if entry.hash != nil {
+			return errWrongType
+		}
+		return entry.str
+	case "SET":
+		return s.set(db, get, now, args)
+	case "SETNX":
+		if len(args) != 2 {
+			return wrongArgs(name)
+		}
+		if get(args[0]) != nil {
+			return 0
+		}
+		db[args[0]] = &redisEntry{str: args[1]}
+		return 1
+	case "SETEX", "PSETEX":
+		if len(args) != 3 {
+			return wrongArgs(name)
+		}
+		ttl, err := strconv.ParseInt(args[1], 10, 64)
+		if err != nil || ttl <= 0 {
+			return errors.New("ERR invalid expire time")
+		}
+		unit := time.Second
+		if name == "PSETEX" {
+			unit = time.Millisecond
+		}
+		db[args[0]] = &redisEntry{str: args[2], expireAt: now.Add(time.Duration(ttl) * unit)}
+		return simpleString("OK")
+	case "DEL", "UNLINK":
+		count := 0
+		for _, key := range args {
+			if get(key) != nil {
+				delete(db, key)
+				count++
+			}
+		}
+		return count
+	case "EXISTS":
+		count := 0
+		for _, key := range args {
+			if get(key) != nil {
+				count++
+			}
+		}
+		return count
+	case "EXPIRE", "PEXPIRE":
+		if len(args) != 2 {
+			return wrongArgs(name)
+		}
+		ttl, err := strconv.ParseInt(args[1], 10, 64)
+		if err != nil {
+			return errors.New("ERR value is not an integer or out of range")
+		}
+		entry := get(args[0])
+		if entry == nil {
+			return 0
+		}
+		unit := time.Second
+		if name == "PEXPIRE" {
+			unit = time.Millisecond
+		}
+		entry.expireAt = now.Add(time.Duration(ttl) * unit)
+		return 1
+	case "PERSIST":
+		if len(args) != 1 {
+			return wrongArgs(name)
+		}
+		entry := get(args[0])
+		if entry == nil || entry.expireAt.IsZero() {
+			return 0
+		}
+		entry.expireAt = time.Time{}
+		return 1
+	case "TTL", "PTTL":
+		if len(args) != 1 {
+			return wrongArgs(name)
+		}
+		entry := get(args[0])
+		switch {
+		case entry == nil:
+			return -2
+		case entry.expireAt.IsZero():
+			return -1
+		case name == "PTTL":
+			return int(entry.expireAt.Sub(now).Milliseconds())
+		default:
+			return int((entry.expireAt.Sub(now) + time.Second - 1) / time.Second)
+		}
+	case "INCR", "DECR", "INCRBY", "DECRBY":
+		return s.incr(db, get, name, args)
+	case "KEYS":
+		if len(args) != 1 {
+			return wrongArgs(name)
+		}
+		keys := make([]string, 0)
+		for key := range db {
+			if matched, _ := path.Match(args[0], key); matched && get(key) != nil {
+				keys = append(keys, key)
+			}
+		}
+		slices.Sort(keys)
+		return keys
+	case "DBSIZE":
+		count := 0
+		for key := range db {
+			if get(key) != nil {
+				count++
+			}
+		}
+		return count
+	case "FLUSHDB":
+		s.dbs[index] = make(map[string]*redisEntry)
+		return simpleString("OK")
+	case "FLUSHALL":
+		s.dbs = make(map[int]map[string]*redisEntry)
+		return simpleString("OK")
+	case "HSET", "HMSET":
+		if len(args) < 3 || len(args)%2 != 1 {
+			return wrongArgs(name)
+		}
+		entry := get(args[0])
+		if entry == nil {
+			entry = &redisEntry{hash: make(map[string]string)}
+			db[args[0]] = entry
+		} else if entry.hash == nil {
+			return errWrongType
+		}
+		added := 0
+		for i := 1; i < len(args); i += 2 {
+			if _, ok := entry.hash[args[i]]; !ok {
+				added++
+			}
+			entry.hash[args[i]] = args[i+1]
+		}
+		if name == "HMSET" {
+			return simpleString("OK")
+		}
+		return added
+	case "HGET":
+		if len(args) != 2 {
+			return wrongArgs(name)
+		}
+		entry := get(args[0])
+		if entry == nil {
+			return nil
+		}
+		if entry.hash == nil {
+			return errWrongType
+		}
+		value, ok := entry.hash[args[1]]
+		if !ok {
+			return nil
+		}
+		return value
+	case "HDEL":
+		if len(args) < 2 {
+			return wrongArgs(name)
+		}
+		entry := get(args[0])
+		if entry == nil {
+			return 0
+		}
+		if entry.hash == nil {
+			return errWrongType
+		}
+		count := 0
+		for _, field := range args[1:] {
+			if _, ok := entry.hash[field]; ok {
+				delete(entry.hash, field)
+				count++
+			}
+		}
+		if len(entry.hash) == 0 {
+			delete(db, args[0])
+		}
+		return count
+	case "HGETALL":
+		if len(args) != 1 {
+			return wrongArgs(name)
+		}
+		entry := get(args[0])
+		if entry == nil {
+			return []string{}
+		}
+		if entry.hash == nil {
+			return errWrongType
+		}
+		result := make([]string, 0, len(entry.hash)*2)
+		for _, field := range slices.Sorted(maps.Keys(entry.hash)) {
+			result = append(result, field, entry.hash[field])
+		}
+		return result
+	default:
+		return fmt.Errorf("ERR unknown command '%s'", strings.ToLower(name))
+	}
+}
+
+// SET key value [EX seconds|PX milliseconds] [NX|XX]
+func (s *RedisServer) set(db map[string]*redisEntry, get func(string) *redisEntry, now time.Time, args []string) any {
+	if len(args) < 2 {
+		return wrongArgs("SET")
+	}
+	entry := &redisEntry{str: args[1]}
+	var nx, xx bool
+	for i := 2; i < len(args); i++ {
+		switch strings.ToUpper(args[i]) {
+		case "NX":
+			nx = true
+		case "XX":
+			xx = true
+		case "EX", "PX":
+			if i+1 >= len(args) {
+				return errors.New("ERR syntax error")
+			}
+			ttl, err := strconv.ParseInt(args[i+1], 10, 64)
+			if err != nil || ttl <= 0 {
+				return errors.New("ERR invalid expire time in 'set' command")
+			}
+			unit := time.Second
+			if strings.ToUpper(args[i]) == "PX" {
+				unit = time.Millisecond
+			}
+			entry.expireAt = now.Add(time.Duration(ttl) * unit)
+			i++
+		default:
+			return errors.New("ERR syntax error")
+		}
+	}
+
+	exists := get(args[0]) != nil
+	if nx && exists || xx && !exists {
+		return nil
+	}
+	db[args[0]] = entry
+	return simpleString("OK")
+}
+
+// INCR、DECR、INCRBY、DECRBY
+func (s *RedisServer) incr(db map[string]*redisEntry, get func(string) *redisEntry, name string, args []string) any {
+	delta := int64(1)
+	switch name {
+	case "INCR", "DECR":
+		if len(args) != 1 {
+			return wrongArgs(name)
+		}
+	default:
+		if len(args) != 2 {
+			return wrongArgs(name)
+		}
+		parsed, err := strconv.ParseInt(args[1], 10, 64)
+		if err != nil {
+			return errors.New("ERR value is not an integer or out of range")
+		}
+		delta = parsed
+	}
+	if name == "DECR" || name == "DECRBY" {
+		delta = -delta
+	}
+
+	entry := get(args[0])
+	if entry == nil {
+		entry = &redisEntry{str: "0"}
+		db[args[0]] = entry
+	}
+	if entry.hash != nil {
+		return errWrongType
+	}
+	value, err := strconv.ParseInt(entry.str, 10, 64)
+	if err != nil {
+		return errors.New("ERR value is not an integer or out of range")
+	}
+	value += delta
+	entry.str = strconv.FormatInt(value, 10)
+	return int(value)
+}
+
+// 读取一条RESP命令，只支持客户端使用的多条批量回复格式
+func readCommand(reader *bufio.Reader) ([]string, error) {
+	line, err := readLine(reader)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.HasPrefix(line, "*") {
+		// 内联命令，如：PING
+		return strings.Fields(line), nil
+	}
+	count, err := strconv.Atoi(line[1:])
+	if err != nil {
+		return nil, fmt.Errorf("invalid multibulk length %q", line)
+	}
+
+	args := make([]string, 0, count)
+	for range count {
+		header, err := readLine(reader)
+		if err != nil {
+			return nil, err
+		}
+		if !strings.HasPrefix(header, "$") {
+			return nil, fmt.Errorf("expected bulk string, got %q", header)
+		}
+		size, err := strconv.Atoi(header[1:])
+		if err != nil || size < 0 {
+			return nil, fmt.Errorf("invalid bulk length %q", header)
+		}
+		buf := make([]byte, size+2)
+		if _, err := io.ReadFull(reader, buf); err != nil {
+			return nil, err
+		}
+		args = append(args, string(buf[:size]))
+	}
+	return args, nil
+}
+
+// 读取一行，去掉末尾的\r\n
+func readLine(reader *bufio.Reader) (string, error) {
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// 按RESP格式写入回复
+func writeReply(writer *bufio.Writer, reply any) {
+	switch reply := reply.(type) {
+	case nil:
+		writer.WriteString("$-1\r\n")
+	case simpleString:
+		writer.WriteString("+" + string(reply) + "\r\n")
+	case error:
+		writer.WriteString("-" + reply.Error() + "\r\n")
+	case int:
+		writer.WriteString(":" + strconv.Itoa(reply) + "\r\n")
+	case string:
+		writer.WriteString("$" + strconv.Itoa(len(reply)) + "\r\n" + reply + "\r\n")
+	case []string:
+		writer.WriteString("*" + strconv.Itoa(len(reply)) + "\r\n")
+		for _, item := range reply {
+			writeReply(writer, item)
+		}
+	}
+}
@@ -0,0 +1,233 @@
+package gostar
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shi-yunsheng/gostar/date"
+	"github.com/shi-yunsheng/gostar/router/handler"
+)
+
+// 健康检查默认配置
+const (
+	defaultLivenessPath       = "/healthz"
+	defaultReadinessPath      = "/readyz"
+	defaultHealthCheckTimeout = 3 * time.Second
+)
+
+// 健康检查配置
+type healthConfig struct {
+	// 是否启用健康检查接口
+	Enable bool `yaml:"enable"`
+	// 存活检查路径，默认/healthz
+	LivenessPath string `yaml:"liveness_path"`
+	// 就绪检查路径，默认/readyz
+	ReadinessPath string `yaml:"readiness_path"`
+	// 单项检查的超时时间，默认3s
+	Timeout string `yaml:"timeout"`
+}
+
+// 健康检查函数
+type HealthCheck func(ctx context.Context) error
+
+// 用户注册的健康检查
+type namedHealthCheck struct {
+	name  string
+	check HealthCheck
+}
+
+// 单项检查结果
+type healthCheckResult struct {
+	Status   string `json:"status"`
+	Duration string `json:"duration"`
+	Error    string `json:"error,omitempty"`
+}
+
+// 获取存活检查路径
+func (c *healthConfig) livenessPath() string {
+	if strings.TrimSpace(c.LivenessPath) == "" {
+		return defaultLivenessPath
+	}
+	return c.LivenessPath
+}
+
+// 获取就绪检查路径
+func (c *healthConfig) readinessPath() string {
+	if strings.TrimSpace(c.ReadinessPath) == "" {
+		return defaultReadinessPath
+	}
+	return c.ReadinessPath
+}
+
+// 获取单项检查的超时时间
+func (c *healthConfig) timeout() time.Duration {
+	if strings.TrimSpace(c.Timeout) == "" {
+		return defaultHealthCheckTimeout
+	}
+	timeout, err := date.ParseTimeDuration(c.Timeout)
+	if err != nil || timeout <= 0 {
+		return defaultHealthCheckTimeout
+	}
+	return timeout
+}
+
+// 添加就绪检查，name会作为检查结果中的键名，同名检查会被替换
+// 数据库和Redis连接会自动检查，无需手动添加
+func (g *goStar) AddHealthCheck(name string, check HealthCheck) {
+	if check == nil {
+		return
+	}
+
+	g.healthChecksLock.Lock()
+	defer g.healthChecksLock.Unlock()
+
+	for i, c := range g.healthChecks {
+		if c.name == name {
+			g.healthChecks[i].check = check
+			return
+		}
+	}
+	g.healthChecks = append(g.healthChecks, namedHealthCheck{name: name, check: check})
+}
+
+// 获取所有就绪检查，包括数据库、Redis和用户注册的检查
+func (g *goStar) getHealthChecks() []namedHealthCheck {
+	var checks []namedHealthCheck
+	for _, name := range g.registry.DBNames() {
+		db := g.registry.GetDB(name)
+		checks = append(checks, namedHealthCheck{name: "database." + name, check: db.Ping})
+	}
+	for _, name := range g.registry.RedisNames() {
+		rdb := g.registry.GetRedis(name)
+		checks = append(checks, namedHealthCheck{name: "redis." + name, check: rdb.Ping})
+	}
+
+	g.healthChecksLock.RLock()
+	checks = append(checks, g.healthChecks...)
+	g.healthChecksLock.RUnlock()
+
+	return checks
+}
+
+// 并发执行所有就绪检查，返回是否全部通过以及每项检查的结果
+func (g *goStar) runHealthChecks(timeout time.Duration) (bool, map[string]healthCheckResult) {
+	checks := g.getHealthChecks()
+	results := make(map[string]healthCheckResult, len(checks))
+
+	var (
+		wg   sync.WaitGroup
+		lock sync.Mutex
+	)
+	for _, c := range checks {
+		wg.Add(1)
+		go func(c namedHealthCheck) {
+			defer wg.Done()
+
+			ctx, cancel := context.WithTimeout(context.Background(), timeout)
+			defer cancel()
+
+			start := time.Now()
+			err := runHealthCheck(ctx, c.check)
+			result := healthCheckResult{Status: "ok", Duration: time.Since(start).String()}
+			if err != nil {
+				result.Status = "fail"
+				result.Error = err.Error()
+			}
+
+			lock.Lock()
+			results[c.name] = result
+			lock.Unlock()
+		}(c)
+	}
+	wg.Wait()
+
+	ok := !slices.ContainsFunc(checks, func(c namedHealthCheck) bool {
+		return results[c.name].Status != "ok"
+	})
+	return ok, results
+}
+
+// 执行单项检查，超时或panic时返回错误
+func runHealthCheck(ctx context.Context, check HealthCheck) error {
+	done := make(chan error, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				done <- fmt.Errorf("panic: %v", r)
+			}
+		}()
+		done <- check(ctx)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// 存活检查，进程能够处理请求即返回200
+func (g *goStar) livenessHandler(w *handler.Response, r handler.Request) any {
+	return map[string]any{"status": "ok"}
+}
+
+// 就绪检查，所有检查通过时返回200，否则返回503，优雅关闭开始后始终返回503
+func (g *goStar) readinessHandler(w *handler.Response, r handler.Request) any {
+	if g.shuttingDown.Load() {
+		w.SetHeader("Content-Type", "application/json; charset=utf-8")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		w.Json(map[string]any{"status": "shutting_down"})
+		return nil
+	}
+
+	ok, results := g.runHealthChecks(g.config.Load().Health.timeout())
+	result := map[string]any{"status": "ok", "checks": results}
+	if !ok {
+		result["status"] = "fail"
+		w.SetHeader("Content-Type", "application/json; charset=utf-8")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		w.Json(result)
+		return nil
+	}
+
+	return result
+}
+
+// 创建HTTP处理器，启用健康检查时在路由之前处理健康检查请求，不经过中间件和认证密钥
+// 配置了管理监听地址时，会标记请求来自的监听地址类型，由路由决定提供哪些路由
+func (g *goStar) newHandler(listener string) http.Handler {
+	config := g.config.Load()
+	var mux http.Handler = g.router.GetMux()
+	if len(config.AdminBind) > 0 {
+		routerMux := mux
+		mux = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			routerMux.ServeHTTP(w, handler.WithListener(r, listener))
+		})
+	}
+
+	health := config.Health
+	if !health.Enable {
+		return mux
+	}
+
+	livenessPath, readinessPath := health.livenessPath(), health.readinessPath()
+	liveness := handler.ToHttpHandler(g.livenessHandler)
+	readiness := handler.ToHttpHandler(g.readinessHandler)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case livenessPath:
+			liveness(w, r)
+		case readinessPath:
+			readiness(w, r)
+		default:
+			mux.ServeHTTP(w, r)
+		}
+	})
+}
@@ -0,0 +1,215 @@
+// 提供框架消息的国际化功能，内置zh-CN和en-US消息目录，支持注册自定义消息目录
+package i18n
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// 内置语言
+const (
+	ZhCN = "zh-CN"
+	EnUS = "en-US"
+)
+
+// 消息目录，键为消息标识，值为消息模板，模板使用fmt格式
+type Catalog map[string]string
+
+var (
+	catalogLock sync.RWMutex
+	// 所有语言的消息目录，键为语言标签
+	catalogs = map[string]Catalog{
+		ZhCN: zhCN,
+		EnUS: enUS,
+	}
+	// 默认语言
+	defaultLang = EnUS
+)
+
+// 注册消息目录，已存在的语言会合并消息，相同键以新注册的为准，可用于覆盖框架内置消息
+func Register(lang string, catalog Catalog) {
+	lang = normalizeTag(lang)
+	if lang == "" {
+		return
+	}
+
+	catalogLock.Lock()
+	defer catalogLock.Unlock()
+
+	// 复制一份，避免修改内置目录或调用方的map
+	merged := make(Catalog, len(catalogs[lang])+len(catalog))
+	for key, message := range catalogs[lang] {
+		merged[key] = message
+	}
+	for key, message := range catalog {
+		merged[key] = message
+	}
+	catalogs[lang] = merged
+}
+
+// 获取已注册的语言列表
+func Languages() []string {
+	catalogLock.RLock()
+	defer catalogLock.RUnlock()
+
+	langs := make([]string, 0, len(catalogs))
+	for lang := range catalogs {
+		langs = append(langs, lang)
+	}
+	slices.Sort(langs)
+	return langs
+}
+
+// 设置默认语言，未注册的语言会被忽略
+func SetDefaultLang(lang string) {
+	if resolved := Resolve(lang); resolved != "" {
+		catalogLock.Lock()
+		defaultLang = resolved
+		catalogLock.Unlock()
+	}
+}
+
+// 获取默认语言，默认en-US
+func GetDefaultLang() string {
+	catalogLock.RLock()
+	defer catalogLock.RUnlock()
+	return defaultLang
+}
+
+// 将语言标签解析为已注册的语言，如：zh、zh-cn、zh_CN => zh-CN，无法解析时返回空字符串
+func Resolve(lang string) string {
+	lang = normalizeTag(lang)
+	if lang == "" {
+		return ""
+	}
+
+	catalogLock.RLock()
+	defer catalogLock.RUnlock()
+
+	if _, ok := catalogs[lang]; ok {
+		return lang
+	}
+	// 按主语言匹配，如：zh-TW => zh-CN，优先选择排序靠前的语言，保证结果稳定
+	base, _, _ := strings.Cut(lang, "-")
+	var matched string
+	for registered := range catalogs {
+		registeredBase, _, _ := strings.Cut(registered, "-")
+		if strings.EqualFold(registeredBase, base) && (matched == "" || registered < matched) {
+			matched = registered
+		}
+	}
+	return matched
+}
+
+// 根据Accept-Language请求头选择语言，没有匹配的语言时使用fallback，fallback也无法解析时使用默认语言
+func Match(acceptLanguage string, fallback string) string {
+	for _, tag := range parseAcceptLanguage(acceptLanguage) {
+		if lang := Resolve(tag); lang != "" {
+			return lang
+		}
+	}
+	if lang := Resolve(fallback); lang != "" {
+		return lang
+	}
+	return GetDefaultLang()
+}
+
+// 翻译消息，args不为空时按fmt格式化
+// 依次从指定语言、默认语言和en-US中查找，都不存在时返回key
+func T(lang string, key string, args ...any) string {
+	message, ok := lookup(lang, key)
+	if !ok {
+		return key
+	}
+	if len(args) > 0 {
+		return fmt.Sprintf(message, args...)
+	}
+	return message
+}
+
+// 查找消息
+func lookup(lang string, key string) (string, bool) {
+	lang = Resolve(lang)
+
+	catalogLock.RLock()
+	defer catalogLock.RUnlock()
+
+	for _, candidate := range []string{lang, defaultLang, EnUS} {
+		if message, ok := catalogs[candidate][key]; ok {
+			return message, true
+		}
+	}
+	return "", false
+}
+
+// 规范化语言标签，如：zh_cn => zh-CN，en => en
+func normalizeTag(tag string) string {
+	tag = strings.TrimSpace(strings.ReplaceAll(tag, "_", "-"))
+	if tag == "" || tag == "*" {
+		return ""
+	}
+	parts := strings.Split(tag, "-")
+	parts[0] = strings.ToLower(parts[0])
+	for i := 1; i < len(parts); i++ {
+		switch len(parts[i]) {
+		case 2:
+			// 地区，如：CN
+			parts[i] = strings.ToUpper(parts[i])
+		case 4:
+			// 文字，如：Hans
+			parts[i] = strings.ToUpper(parts[i][:1]) + strings.ToLower(parts[i][1:])
+		default:
+			parts[i] = strings.ToLower(parts[i])
+		}
+	}
+	return strings.Join(parts, "-")
+}
+
+// 解析Accept-Language请求头，按权重从高到低返回语言标签，如：zh-CN,zh;q=0.9,en;q=0.8
+func parseAcceptLanguage(header string) []string {
+	type weightedTag struct {
+		tag    string
+		weight float64
+	}
+
+	tags := make([]weightedTag, 0)
+	for part := range strings.SplitSeq(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		tag = strings.TrimSpace(tag)
+		if tag == "" {
+			continue
+		}
+		weight := 1.0
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(q, 64)
+			if err != nil {
+				continue
+			}
+			weight = parsed
+		}
+		if weight <= 0 {
+			continue
+		}
+		tags = append(tags, weightedTag{tag: tag, weight: weight})
+	}
+	// 稳定排序，权重相同时保持请求头中的顺序
+	slices.SortStableFunc(tags, func(a, b weightedTag) int {
+		switch {
+		case a.weight > b.weight:
+			return -1
+		case a.weight < b.weight:
+			return 1
+		default:
+			return 0
+		}
+	})
+
+	result := make([]string, len(tags))
+	for i, tag := range tags {
+		result[i] = tag.tag
+	}
+	return result
+}
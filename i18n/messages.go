@@ -0,0 +1,59 @@
+package i18n
+
+// 框架消息的键
+const (
+	BadRequest                   = "bad_request"
+	BadRequestMessage            = "bad_request.message"
+	Unauthorized                 = "unauthorized"
+	UnauthorizedMessage          = "unauthorized.message"
+	Forbidden                    = "forbidden"
+	ForbiddenMessage             = "forbidden.message"
+	NotFound                     = "not_found"
+	NotFoundMessage              = "not_found.message"
+	MethodNotAllowed             = "method_not_allowed"
+	MethodNotAllowedMessage      = "method_not_allowed.message"
+	RequestEntityTooLarge        = "request_entity_too_large"
+	RequestEntityTooLargeMessage = "request_entity_too_large.message"
+	InternalServerError          = "internal_server_error"
+	InternalServerErrorMessage   = "internal_server_error.message"
+	// 错误详情，参数为错误信息
+	ErrorDetail = "error.detail"
+)
+
+// 英文消息目录
+var enUS = Catalog{
+	BadRequest:                   "Bad Request",
+	BadRequestMessage:            "Sorry, the request is invalid.",
+	Unauthorized:                 "Unauthorized",
+	UnauthorizedMessage:          "Sorry, you are not authorized to access this page.",
+	Forbidden:                    "Forbidden",
+	ForbiddenMessage:             "Sorry, you are not allowed to access this page.",
+	NotFound:                     "Not Found",
+	NotFoundMessage:              "Sorry, the page you visited does not exist.",
+	MethodNotAllowed:             "Method Not Allowed",
+	MethodNotAllowedMessage:      "Sorry, the method you used is not allowed.",
+	RequestEntityTooLarge:        "Request Entity Too Large",
+	RequestEntityTooLargeMessage: "Sorry, the request body is too large.",
+	InternalServerError:          "Internal Server Error",
+	InternalServerErrorMessage:   "Sorry, the server is busy, please try again later.",
+	ErrorDetail:                  " ERROR: %s",
+}
+
+// 简体中文消息目录
+var zhCN = Catalog{
+	BadRequest:                   "请求错误",
+	BadRequestMessage:            "抱歉，请求参数不正确。",
+	Unauthorized:                 "未授权",
+	UnauthorizedMessage:          "抱歉，您没有权限访问该页面。",
+	Forbidden:                    "禁止访问",
+	ForbiddenMessage:             "抱歉，您被禁止访问该页面。",
+	NotFound:                     "页面不存在",
+	NotFoundMessage:              "抱歉，您访问的页面不存在。",
+	MethodNotAllowed:             "请求方法不允许",
+	MethodNotAllowedMessage:      "抱歉，不允许使用该请求方法。",
+	RequestEntityTooLarge:        "请求体过大",
+	RequestEntityTooLargeMessage: "抱歉，请求体超出大小限制。",
+	InternalServerError:          "服务器内部错误",
+	InternalServerErrorMessage:   "抱歉，服务器繁忙，请稍后再试。",
+	ErrorDetail:                  "错误：%s",
+}
@@ -0,0 +1,106 @@
+package i18n
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/go-playground/locales"
+	"github.com/go-playground/locales/en"
+	"github.com/go-playground/locales/zh"
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+	enTranslations "github.com/go-playground/validator/v10/translations/en"
+	zhTranslations "github.com/go-playground/validator/v10/translations/zh"
+)
+
+var (
+	validatorOnce sync.Once
+	validatorLock sync.RWMutex
+	// 共享的校验器，已注册所有语言的翻译
+	validate *validator.Validate
+	// 校验错误翻译器，键为语言标签
+	translators = map[string]ut.Translator{}
+)
+
+// 获取共享的校验器，字段名使用json标签，已注册zh-CN和en-US的错误翻译
+func Validator() *validator.Validate {
+	validatorOnce.Do(func() {
+		validate = validator.New()
+		validate.RegisterTagNameFunc(func(field reflect.StructField) string {
+			name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+			if name == "-" {
+				return ""
+			}
+			if name == "" {
+				return field.Name
+			}
+			return name
+		})
+		registerValidatorTranslation(ZhCN, zh.New(), zhTranslations.RegisterDefaultTranslations)
+		registerValidatorTranslation(EnUS, en.New(), enTranslations.RegisterDefaultTranslations)
+	})
+	return validate
+}
+
+// 注册校验错误翻译，locale为github.com/go-playground/locales中对应的语言，
+// register为github.com/go-playground/validator/v10/translations中对应语言的RegisterDefaultTranslations或自定义的注册函数
+// 需要在处理请求前调用
+func RegisterValidatorTranslation(lang string, locale locales.Translator, register func(v *validator.Validate, trans ut.Translator) error) error {
+	Validator()
+	return registerValidatorTranslation(normalizeTag(lang), locale, register)
+}
+
+// 注册校验错误翻译
+func registerValidatorTranslation(lang string, locale locales.Translator, register func(v *validator.Validate, trans ut.Translator) error) error {
+	if lang == "" {
+		return errors.New("lang is empty")
+	}
+	trans, _ := ut.New(locale, locale).GetTranslator(locale.Locale())
+	if err := register(validate, trans); err != nil {
+		return err
+	}
+
+	validatorLock.Lock()
+	translators[lang] = trans
+	validatorLock.Unlock()
+	return nil
+}
+
+// 获取校验错误翻译器，依次使用指定语言、默认语言和en-US
+func getTranslator(lang string) ut.Translator {
+	Validator()
+	lang = Resolve(lang)
+	defaultLang := GetDefaultLang()
+
+	validatorLock.RLock()
+	defer validatorLock.RUnlock()
+
+	for _, candidate := range []string{lang, defaultLang, EnUS} {
+		if trans, ok := translators[candidate]; ok {
+			return trans
+		}
+	}
+	return nil
+}
+
+// 翻译校验错误，返回字段路径与错误信息的映射，如：{"address.city": "city为必填字段"}，err不是校验错误时返回false
+func TranslateValidationErrors(lang string, err error) (map[string]string, bool) {
+	var validationErrors validator.ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		return nil, false
+	}
+
+	trans := getTranslator(lang)
+	result := make(map[string]string, len(validationErrors))
+	for _, fieldErr := range validationErrors {
+		// 去掉最外层的结构体名
+		field := fieldErr.Namespace()
+		if _, after, ok := strings.Cut(field, "."); ok {
+			field = after
+		}
+		result[field] = fieldErr.Translate(trans)
+	}
+	return result, true
+}
@@ -0,0 +1,22 @@
+package gostar
+
+import (
+	"strings"
+
+	"github.com/shi-yunsheng/gostar/i18n"
+)
+
+// 初始化语言配置，默认语言为进程级配置，只由默认实例设置，各实例的语言通过请求上下文区分
+func (g *goStar) initLang() {
+	lang := g.config.Load().Lang
+	if lang == "" {
+		return
+	}
+	if i18n.Resolve(lang) == "" {
+		g.logger.W("Language %s has no registered catalog, supported languages: %s", lang, strings.Join(i18n.Languages(), ", "))
+		return
+	}
+	if g.isDefault {
+		i18n.SetDefaultLang(lang)
+	}
+}
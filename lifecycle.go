@@ -0,0 +1,109 @@
+package gostar
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// 生命周期钩子的默认超时时间
+const defaultHookTimeout = 30 * time.Second
+
+// 生命周期钩子，ctx在超时后会被取消
+type Hook func(ctx context.Context) error
+
+// 生命周期钩子及其超时时间
+type lifecycleHook struct {
+	hook    Hook
+	timeout time.Duration
+}
+
+// 注册启动钩子，在开始监听之前按注册顺序执行，如：执行数据库迁移、预热缓存
+// timeout为钩子的超时时间，默认30s，任意钩子返回错误时Run将返回该错误且不会开始监听
+func (g *goStar) OnStart(hook Hook, timeout ...time.Duration) {
+	g.addHook(&g.startHooks, hook, timeout...)
+}
+
+// 注册就绪钩子，在开始监听之后按注册顺序执行，如：启动后台任务、注册服务发现
+// timeout为钩子的超时时间，默认30s，任意钩子返回错误时会关闭服务器，Run将返回该错误
+func (g *goStar) OnReady(hook Hook, timeout ...time.Duration) {
+	g.addHook(&g.readyHooks, hook, timeout...)
+}
+
+// 注册关闭钩子，优雅关闭时在服务器停止接收请求之后、关闭数据库和Redis之前按注册顺序的逆序执行
+// timeout为钩子的超时时间，默认30s，钩子返回的错误会合并到Shutdown的返回值中
+func (g *goStar) OnShutdown(hook Hook, timeout ...time.Duration) {
+	g.addHook(&g.shutdownHooks, hook, timeout...)
+}
+
+// 添加钩子
+func (g *goStar) addHook(hooks *[]lifecycleHook, hook Hook, timeout ...time.Duration) {
+	if hook == nil {
+		return
+	}
+
+	h := lifecycleHook{hook: hook, timeout: defaultHookTimeout}
+	if len(timeout) > 0 && timeout[0] > 0 {
+		h.timeout = timeout[0]
+	}
+
+	g.hooksLock.Lock()
+	defer g.hooksLock.Unlock()
+
+	*hooks = append(*hooks, h)
+}
+
+// 获取钩子副本，避免执行钩子时持有锁
+func (g *goStar) getHooks(hooks []lifecycleHook) []lifecycleHook {
+	g.hooksLock.Lock()
+	defer g.hooksLock.Unlock()
+
+	return append([]lifecycleHook(nil), hooks...)
+}
+
+// 执行单个钩子，钩子panic时转换为错误
+func (h lifecycleHook) run() (err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), h.timeout)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				done <- fmt.Errorf("panic: %v", r)
+			}
+		}()
+		done <- h.hook(ctx)
+	}()
+
+	select {
+	case err = <-done:
+		return err
+	case <-ctx.Done():
+		return fmt.Errorf("timed out after %v: %w", h.timeout, ctx.Err())
+	}
+}
+
+// 按注册顺序执行钩子，遇到错误时立即返回
+func (g *goStar) runHooks(stage string, hooks []lifecycleHook) error {
+	for i, h := range g.getHooks(hooks) {
+		if err := h.run(); err != nil {
+			return fmt.Errorf("%s hook #%d failed: %w", stage, i+1, err)
+		}
+	}
+	return nil
+}
+
+// 按注册顺序的逆序执行关闭钩子，返回所有钩子的错误
+func (g *goStar) runShutdownHooks() error {
+	hooks := g.getHooks(g.shutdownHooks)
+
+	var errs []error
+	for i := len(hooks) - 1; i >= 0; i-- {
+		if err := hooks[i].run(); err != nil {
+			errs = append(errs, fmt.Errorf("shutdown hook #%d failed: %w", i+1, err))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,334 @@
+package gostar
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/user"
+	"strconv"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+// systemd传递的第一个文件描述符
+const systemdListenFDsStart = 3
+
+// 绑定地址列表，配置中可以是单个地址或地址列表
+// 支持的地址格式：
+// host:port：监听TCP地址，如：0.0.0.0:8000
+// unix:/path/app.sock：监听Unix域套接字
+// fd:3：使用继承的文件描述符
+// systemd或systemd:name：使用systemd套接字激活传递的文件描述符（LISTEN_FDS），指定name时只使用LISTEN_FDNAMES中对应名称的文件描述符
+type bindList []string
+
+// 解析绑定地址，支持字符串和字符串列表
+func (b *bindList) UnmarshalYAML(node *yaml.Node) error {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		if node.Tag == "!!null" || node.Value == "" {
+			*b = nil
+			return nil
+		}
+		*b = bindList{node.Value}
+		return nil
+	case yaml.SequenceNode:
+		var list []string
+		if err := node.Decode(&list); err != nil {
+			return err
+		}
+		*b = list
+		return nil
+	default:
+		return &yaml.TypeError{Errors: []string{
+			fmt.Sprintf("line %d: cannot unmarshal bind address, must be a string or a list of strings", node.Line),
+		}}
+	}
+}
+
+// 获取第一个绑定地址，没有时返回空字符串
+func (b bindList) first() string {
+	if len(b) == 0 {
+		return ""
+	}
+	return b[0]
+}
+
+// 获取第一个TCP绑定地址，没有时返回空字符串
+func (b bindList) firstTCP() string {
+	for _, address := range b {
+		if getBindNetwork(address) == "tcp" {
+			return address
+		}
+	}
+	return ""
+}
+
+// Unix域套接字配置
+type unixSocketConfig struct {
+	// 套接字文件权限，如："0660"
+	Mode string `yaml:"mode"`
+	// 套接字文件所有者，用户名或UID
+	Owner string `yaml:"owner"`
+	// 套接字文件所属组，组名或GID
+	Group string `yaml:"group"`
+}
+
+// 监听器及其绑定地址
+type bindListener struct {
+	net.Listener
+	// 监听地址类型，如：public、admin、redirect
+	kind string
+	// 配置中的绑定地址
+	address string
+}
+
+// 获取绑定地址的类型：tcp、unix、fd或systemd
+func getBindNetwork(address string) string {
+	switch {
+	case strings.HasPrefix(address, "unix:"):
+		return "unix"
+	case strings.HasPrefix(address, "fd:"):
+		return "fd"
+	case address == "systemd" || strings.HasPrefix(address, "systemd:"):
+		return "systemd"
+	default:
+		return "tcp"
+	}
+}
+
+// 监听所有绑定地址，任意地址监听失败时关闭已创建的监听器
+// 优先使用重启时从旧进程继承的监听器，监听成功后会记录到当前实例中，用于重启时传递给新进程
+func (g *goStar) listenAll(kind string, addresses []string) ([]*bindListener, error) {
+	var listeners []*bindListener
+	for _, address := range addresses {
+		lns, err := takeInheritedListeners(kind, address)
+		if err == nil && len(lns) == 0 {
+			lns, err = g.listen(address)
+		}
+		if err != nil {
+			closeListeners(listeners)
+			return nil, fmt.Errorf("listen on %s failed: %w", address, err)
+		}
+		for _, ln := range lns {
+			ln.kind = kind
+		}
+		listeners = append(listeners, lns...)
+	}
+
+	g.listenersLock.Lock()
+	g.listeners = append(g.listeners, listeners...)
+	g.listenersLock.Unlock()
+
+	return listeners, nil
+}
+
+// 关闭监听器
+func closeListeners(listeners []*bindListener) {
+	for _, ln := range listeners {
+		ln.Close()
+	}
+}
+
+// 监听绑定地址，systemd地址可能对应多个监听器
+func (g *goStar) listen(address string) ([]*bindListener, error) {
+	var (
+		ln  net.Listener
+		err error
+	)
+	switch getBindNetwork(address) {
+	case "unix":
+		ln, err = listenUnix(strings.TrimPrefix(address, "unix:"), g.config.Load().UnixSocket)
+	case "fd":
+		ln, err = listenFD(strings.TrimPrefix(address, "fd:"))
+	case "systemd":
+		_, name, _ := strings.Cut(address, ":")
+		return listenSystemd(address, name)
+	default:
+		ln, err = net.Listen("tcp", address)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return []*bindListener{{Listener: ln, address: address}}, nil
+}
+
+// 监听Unix域套接字，套接字文件已存在且无法连接时会先删除
+func listenUnix(path string, c unixSocketConfig) (net.Listener, error) {
+	if info, err := os.Stat(path); err == nil && info.Mode()&os.ModeSocket != 0 {
+		if conn, err := net.Dial("unix", path); err == nil {
+			conn.Close()
+			return nil, fmt.Errorf("unix socket %s is already in use", path)
+		}
+		if err := os.Remove(path); err != nil {
+			return nil, err
+		}
+	}
+
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.apply(path); err != nil {
+		ln.Close()
+		return nil, err
+	}
+	return ln, nil
+}
+
+// 设置套接字文件的权限和所有者
+func (c *unixSocketConfig) apply(path string) error {
+	if strings.TrimSpace(c.Mode) != "" {
+		mode, err := parseFileMode(c.Mode)
+		if err != nil {
+			return err
+		}
+		if err := os.Chmod(path, mode); err != nil {
+			return err
+		}
+	}
+
+	if strings.TrimSpace(c.Owner) == "" && strings.TrimSpace(c.Group) == "" {
+		return nil
+	}
+	uid, gid := -1, -1
+	if strings.TrimSpace(c.Owner) != "" {
+		id, err := lookupUserID(c.Owner)
+		if err != nil {
+			return err
+		}
+		uid = id
+	}
+	if strings.TrimSpace(c.Group) != "" {
+		id, err := lookupGroupID(c.Group)
+		if err != nil {
+			return err
+		}
+		gid = id
+	}
+	return os.Chown(path, uid, gid)
+}
+
+// 解析八进制文件权限，如："0660"
+func parseFileMode(mode string) (os.FileMode, error) {
+	value, err := strconv.ParseUint(strings.TrimSpace(mode), 8, 32)
+	if err != nil || value > 0o777 {
+		return 0, fmt.Errorf("invalid file mode %q, must be octal like 0660", mode)
+	}
+	return os.FileMode(value), nil
+}
+
+// 根据用户名或UID获取UID
+func lookupUserID(name string) (int, error) {
+	if id, err := strconv.Atoi(name); err == nil {
+		return id, nil
+	}
+	u, err := user.Lookup(name)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(u.Uid)
+}
+
+// 根据组名或GID获取GID
+func lookupGroupID(name string) (int, error) {
+	if id, err := strconv.Atoi(name); err == nil {
+		return id, nil
+	}
+	g, err := user.LookupGroup(name)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(g.Gid)
+}
+
+// 使用继承的文件描述符创建监听器
+func listenFD(fd string) (net.Listener, error) {
+	n, err := strconv.Atoi(fd)
+	if err != nil || n < 0 {
+		return nil, fmt.Errorf("invalid file descriptor %q", fd)
+	}
+
+	file := os.NewFile(uintptr(n), "fd:"+fd)
+	if file == nil {
+		return nil, fmt.Errorf("invalid file descriptor %q", fd)
+	}
+	defer file.Close()
+
+	return net.FileListener(file)
+}
+
+// systemd传递的文件描述符
+type systemdFile struct {
+	file *os.File
+	name string
+}
+
+var (
+	systemdFiles     []*systemdFile
+	systemdFilesOnce sync.Once
+	systemdFilesLock sync.Mutex
+)
+
+// 获取systemd套接字激活传递的文件描述符，读取后会清除相关环境变量，避免被子进程继承
+func getSystemdFiles() []*systemdFile {
+	systemdFilesOnce.Do(func() {
+		defer func() {
+			os.Unsetenv("LISTEN_PID")
+			os.Unsetenv("LISTEN_FDS")
+			os.Unsetenv("LISTEN_FDNAMES")
+		}()
+
+		if pid := os.Getenv("LISTEN_PID"); pid != "" && pid != strconv.Itoa(os.Getpid()) {
+			return
+		}
+		count, err := strconv.Atoi(os.Getenv("LISTEN_FDS"))
+		if err != nil || count <= 0 {
+			return
+		}
+		names := strings.Split(os.Getenv("LISTEN_FDNAMES"), ":")
+
+		for i := 0; i < count; i++ {
+			fd := systemdListenFDsStart + i
+			name := ""
+			if i < len(names) {
+				name = names[i]
+			}
+			systemdFiles = append(systemdFiles, &systemdFile{
+				file: os.NewFile(uintptr(fd), "systemd:"+name),
+				name: name,
+			})
+		}
+	})
+	return systemdFiles
+}
+
+// 使用systemd套接字激活传递的文件描述符创建监听器，name不为空时只使用对应名称的文件描述符
+// 每个文件描述符只能使用一次
+func listenSystemd(address string, name string) ([]*bindListener, error) {
+	systemdFilesLock.Lock()
+	defer systemdFilesLock.Unlock()
+
+	var listeners []*bindListener
+	for _, f := range getSystemdFiles() {
+		if f.file == nil || (name != "" && f.name != name) {
+			continue
+		}
+
+		ln, err := net.FileListener(f.file)
+		if err != nil {
+			closeListeners(listeners)
+			return nil, err
+		}
+		f.file.Close()
+		f.file = nil
+		listeners = append(listeners, &bindListener{Listener: ln, address: address})
+	}
+
+	if len(listeners) == 0 {
+		return nil, errors.New("no file descriptor passed by systemd (LISTEN_FDS)")
+	}
+	return listeners, nil
+}
@@ -2,8 +2,6 @@ package gostar
 
 import (
 	"strings"
-
-	"github.com/shi-yunsheng/gostar/logger"
 )
 
 // 日志配置
@@ -16,26 +14,33 @@ type logConfig struct {
 	MaxFileSize      string `yaml:"max_file_size"`
 }
 
-// 初始化日志
+// 初始化日志，配置热加载时也会重新调用
 func (g *goStar) initLog() {
-	config := g.config.Log
+	config := g.config.Load().Log
 
-	if !config.EnablePrint {
-		logger.DisablePrint()
-	}
-	if strings.TrimSpace(config.MaxFileSize) != "" {
-		logger.SetMaxSingleLogFileSize(config.MaxFileSize)
-	}
+	g.logger.SetMaxSingleLogFileSize(config.MaxFileSize)
+	// 先设置日志保存，避免禁用打印时误报未启用保存
 	if config.EnableSave {
-		logger.EnableSave()
+		g.logger.EnableSave()
+	} else {
+		g.logger.DisableSave()
+	}
+	if config.EnablePrint {
+		g.logger.EnablePrint()
+	} else {
+		g.logger.DisablePrint()
 	}
 	if strings.TrimSpace(config.SavePath) != "" {
-		logger.SetSavePath(config.SavePath)
+		g.logger.SetSavePath(config.SavePath)
 	}
 	if strings.TrimSpace(config.MaxSaveDays) != "" {
-		logger.SetMaxSaveDays(config.MaxSaveDays)
+		g.logger.SetMaxSaveDays(config.MaxSaveDays)
+	} else {
+		g.logger.SetMaxSaveDays("7d")
 	}
 	if config.EnableAutoDelete {
-		logger.EnableAutoDelete()
+		g.logger.EnableAutoDelete()
+	} else {
+		g.logger.DisableAutoDelete()
 	}
 }
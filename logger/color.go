@@ -35,12 +35,12 @@ func getLogLevelColor(level string) string {
 }
 
 // 获取日志级别消息
-func getLogLevelMessage(level string, message string) string {
+func (l *Logger) getLogLevelMessage(level string, message string) string {
 	color := getLogLevelColor(level)
 	// 根据日期格式获取日期时间
-	datetime := date.GetToday(dateFormat)
+	datetime := date.GetToday(l.dateFormat)
 	// 使用日志格式
-	message = fmt.Sprintf(logFormat, datetime, level[0:1], message)
+	message = fmt.Sprintf(l.logFormat, datetime, level[0:1], message)
 
 	return color + message + logReset
 }
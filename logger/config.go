@@ -2,79 +2,165 @@ package logger
 
 import (
 	"fmt"
-	"os"
-	"path/filepath"
 	"strings"
 
 	"github.com/shi-yunsheng/gostar/date"
 	"github.com/shi-yunsheng/gostar/utils"
 )
 
-var (
-	enablePrint = true
-	enableSave  = false
-	savePath    = func() string {
-		execPath, _ := os.Executable()
-		execDir := filepath.Dir(execPath)
-		return execDir + "/logs/"
-	}()
-	enableAutoDelete = false
-	maxSaveDays      = "7d"
-	dateFormat       = date.FORMAT_DATETIME
-	logFormat        = "[%s] [%s] %s"
-)
-
 // 启用日志打印
-func DisablePrint() {
-	enablePrint = false
-	if !enableSave {
+func (l *Logger) EnablePrint() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.enablePrint = true
+}
+
+// 禁用日志打印
+func (l *Logger) DisablePrint() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.enablePrint = false
+	if !l.enableSave {
 		fmt.Println("Log printing is disabled and log saving is not enabled. Some important information may be missed.")
 	}
 }
 
 // 启用日志保存
-func EnableSave() {
-	enableSave = true
+func (l *Logger) EnableSave() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.enableSave = true
+}
+
+// 禁用日志保存
+func (l *Logger) DisableSave() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.enableSave = false
 }
 
 // 设置日志保存路径
-func SetSavePath(path string) {
-	savePath = path
+func (l *Logger) SetSavePath(path string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.savePath = path
 }
 
 // 启用自动删除
-func EnableAutoDelete() {
-	enableAutoDelete = true
-	go autoDeleteLogs()
+func (l *Logger) EnableAutoDelete() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.enableAutoDelete = true
+	// 只启动一个清理协程，重复调用无副作用，关闭后不再启动
+	if l.closed {
+		return
+	}
+	l.autoDeleteOnce.Do(func() {
+		l.wg.Add(1)
+		go l.autoDeleteLogs()
+	})
+}
+
+// 禁用自动删除
+func (l *Logger) DisableAutoDelete() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.enableAutoDelete = false
 }
 
 // 日志保存天数，仅支持天数，如：1d, 2d, 3d，默认7d
-func SetMaxSaveDays(days string) {
-	maxSaveDays = days
+func (l *Logger) SetMaxSaveDays(days string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.maxSaveDays = days
 }
 
-// 单个日志文件最大大小，支持单位：B, KB, MB, GB, TB，如：1KB, 2.5MB, 1GB，默认不限制
-func SetMaxSingleLogFileSize(size string) {
+// 单个日志文件最大大小，支持单位：B, KB, MB, GB, TB，如：1KB, 2.5MB, 1GB，默认不限制，设置为None或空则取消限制
+func (l *Logger) SetMaxSingleLogFileSize(size string) {
+	var maxLogSize int64 = -1
 	if strings.TrimSpace(size) != "None" && strings.TrimSpace(size) != "" {
-		size, err := utils.ParseSize(size)
+		parsed, err := utils.ParseSize(size)
 		if err != nil {
 			panic(err)
 		}
-		maxLogSize = size
+		maxLogSize = parsed
 	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.maxLogSize = maxLogSize
+}
+
+// 设置日期格式，默认2006-01-02 15:04:05
+func (l *Logger) SetDateFormat(format date.DateFormat) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.dateFormat = format
+}
+
+// 设置日志格式，默认：[%s] %s: %s，第一个 %s 是时间，第二个 %s 是级别，第三个 %s 是消息
+func (l *Logger) SetLogFormat(format string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.logFormat = format
+}
+
+// 启用默认日志器的日志打印
+func EnablePrint() {
+	defaultLogger.EnablePrint()
 }
 
-// 设置通道缓冲区大小
+// 禁用默认日志器的日志打印
+func DisablePrint() {
+	defaultLogger.DisablePrint()
+}
+
+// 启用默认日志器的日志保存
+func EnableSave() {
+	defaultLogger.EnableSave()
+}
+
+// 禁用默认日志器的日志保存
+func DisableSave() {
+	defaultLogger.DisableSave()
+}
+
+// 设置默认日志器的日志保存路径
+func SetSavePath(path string) {
+	defaultLogger.SetSavePath(path)
+}
+
+// 启用默认日志器的自动删除
+func EnableAutoDelete() {
+	defaultLogger.EnableAutoDelete()
+}
+
+// 禁用默认日志器的自动删除
+func DisableAutoDelete() {
+	defaultLogger.DisableAutoDelete()
+}
+
+// 默认日志器的日志保存天数，仅支持天数，如：1d, 2d, 3d，默认7d
+func SetMaxSaveDays(days string) {
+	defaultLogger.SetMaxSaveDays(days)
+}
+
+// 默认日志器的单个日志文件最大大小，支持单位：B, KB, MB, GB, TB，如：1KB, 2.5MB, 1GB，默认不限制
+func SetMaxSingleLogFileSize(size string) {
+	defaultLogger.SetMaxSingleLogFileSize(size)
+}
+
+// 设置通道缓冲区大小，仅对之后新建的日志器有效
 func SetChannelBufferSize(size int) {
 	chanBufferSize = size
 }
 
-// 设置日期格式，默认2006-01-02 15:04:05
+// 设置默认日志器的日期格式，默认2006-01-02 15:04:05
 func SetDateFormat(format date.DateFormat) {
-	dateFormat = format
+	defaultLogger.SetDateFormat(format)
 }
 
-// 设置日志格式，默认：[%s] %s: %s，第一个 %s 是时间，第二个 %s 是级别，第三个 %s 是消息
+// 设置默认日志器的日志格式，默认：[%s] %s: %s，第一个 %s 是时间，第二个 %s 是级别，第三个 %s 是消息
 func SetLogFormat(format string) {
-	logFormat = format
+	defaultLogger.SetLogFormat(format)
 }
@@ -3,16 +3,17 @@ package logger
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
 	"sync"
+
+	"github.com/shi-yunsheng/gostar/date"
 )
 
 var (
-	logChan        chan *logMessage
 	chanBufferSize = 10000
-	wg             sync.WaitGroup
-	once           sync.Once
-	done           chan struct{}
-	maxLogSize     int64 = -1
+	// 默认日志器，包级别的日志函数都使用它
+	defaultLogger = New()
 )
 
 // 日志消息结构
@@ -21,95 +22,181 @@ type logMessage struct {
 	message string
 }
 
-// 初始化日志系统
-func init() {
-	initLogger()
+// 日志器，每个日志器拥有独立的配置和消费协程
+type Logger struct {
+	// 保护日志配置和关闭状态，配置热加载时会在其他协程中修改配置
+	mu sync.RWMutex
+	// 是否已关闭，关闭后的日志直接同步写入标准错误
+	closed           bool
+	enablePrint      bool
+	enableSave       bool
+	savePath         string
+	enableAutoDelete bool
+	maxSaveDays      string
+	dateFormat       date.DateFormat
+	logFormat        string
+	maxLogSize       int64
+	// 最后一个日志文件索引，只在消费协程中使用
+	lastLogFileIndex int
+
+	logChan        chan *logMessage
+	wg             sync.WaitGroup
+	done           chan struct{}
+	closeOnce      sync.Once
+	autoDeleteOnce sync.Once
 }
 
-// 初始化日志系统
-func initLogger() {
-	once.Do(func() {
-		logChan = make(chan *logMessage, chanBufferSize)
-		done = make(chan struct{})
+// 新建日志器
+func New() *Logger {
+	l := &Logger{
+		enablePrint: true,
+		savePath: func() string {
+			execPath, _ := os.Executable()
+			execDir := filepath.Dir(execPath)
+			return execDir + "/logs/"
+		}(),
+		maxSaveDays:      "7d",
+		dateFormat:       date.FORMAT_DATETIME,
+		logFormat:        "[%s] [%s] %s",
+		maxLogSize:       -1,
+		lastLogFileIndex: -1,
+		logChan:          make(chan *logMessage, chanBufferSize),
+		done:             make(chan struct{}),
+	}
 
-		wg.Add(1)
-		go consumeLogs()
-	})
+	l.wg.Add(1)
+	go l.consumeLogs()
+
+	return l
+}
+
+// 获取默认日志器
+func Default() *Logger {
+	return defaultLogger
 }
 
 // 从通道消费日志
-func consumeLogs() {
-	defer wg.Done()
+func (l *Logger) consumeLogs() {
+	defer l.wg.Done()
 
 	for {
 		select {
-		case msg := <-logChan:
-			formattedMsg := getLogLevelMessage(msg.level, msg.message)
-			saveLog(formattedMsg)
-
-			if enablePrint {
-				fmt.Println(formattedMsg)
-			}
-
-		case <-done:
-			for len(logChan) > 0 {
-				msg := <-logChan
-				formattedMsg := getLogLevelMessage(msg.level, msg.message)
-				saveLog(formattedMsg)
+		case msg := <-l.logChan:
+			l.output(msg)
 
-				if enablePrint {
-					fmt.Println(formattedMsg)
-				}
+		case <-l.done:
+			for len(l.logChan) > 0 {
+				l.output(<-l.logChan)
 			}
 			return
 		}
 	}
 }
 
+// 输出日志
+func (l *Logger) output(msg *logMessage) {
+	l.mu.RLock()
+	formattedMsg := l.getLogLevelMessage(msg.level, msg.message)
+	enablePrint := l.enablePrint
+	l.mu.RUnlock()
+	l.saveLog(formattedMsg)
+
+	if enablePrint {
+		fmt.Println(formattedMsg)
+	}
+}
+
 // 基础打印
-func basePrint(level string, message string, args ...any) {
+func (l *Logger) basePrint(level string, message string, args ...any) {
 	message = fmt.Sprintf(message, args...)
 
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	// 消费协程已退出，同步写入标准错误，避免日志丢失
+	if l.closed {
+		fmt.Fprintln(os.Stderr, l.getLogLevelMessage(level, message))
+		return
+	}
 	select {
-	case logChan <- &logMessage{level: level, message: message}:
+	case l.logChan <- &logMessage{level: level, message: message}:
 	default:
 		fmt.Printf("[WARN] Log channel is full, message dropped: %s\n", message)
 	}
 }
 
-// 优雅关闭
+// 优雅关闭，会先输出通道中剩余的日志并停止自动删除协程，重复调用无副作用，关闭后的日志同步写入标准错误
+func (l *Logger) Close() {
+	l.closeOnce.Do(func() {
+		// 之后的日志不再进入通道，已进入通道的日志由消费协程输出
+		l.mu.Lock()
+		l.closed = true
+		l.mu.Unlock()
+		close(l.done)
+		l.wg.Wait()
+	})
+}
+
+// 信息打印
+func (l *Logger) I(message string, args ...any) {
+	l.basePrint("INFO", message, args...)
+}
+
+// 警告打印
+func (l *Logger) W(message string, args ...any) {
+	l.basePrint("WARN", message, args...)
+}
+
+// 错误打印
+func (l *Logger) E(message string, args ...any) {
+	l.basePrint("ERROR", message, args...)
+}
+
+// 成功打印
+func (l *Logger) S(message string, args ...any) {
+	l.basePrint("SUCCESS", message, args...)
+}
+
+// 调试打印
+func (l *Logger) D(message string, args ...any) {
+	l.basePrint("DEBUG", message, args...)
+}
+
+// 普通打印
+func (l *Logger) P(message string, args ...any) {
+	l.basePrint("PRINT", message, args...)
+}
+
+// 优雅关闭默认日志器
 func Close() {
-	close(done)
-	wg.Wait()
-	close(logChan)
+	defaultLogger.Close()
 }
 
 // 信息打印
 func I(message string, args ...any) {
-	basePrint("INFO", message, args...)
+	defaultLogger.I(message, args...)
 }
 
 // 警告打印
 func W(message string, args ...any) {
-	basePrint("WARN", message, args...)
+	defaultLogger.W(message, args...)
 }
 
 // 错误打印
 func E(message string, args ...any) {
-	basePrint("ERROR", message, args...)
+	defaultLogger.E(message, args...)
 }
 
 // 成功打印
 func S(message string, args ...any) {
-	basePrint("SUCCESS", message, args...)
+	defaultLogger.S(message, args...)
 }
 
 // 调试打印
 func D(message string, args ...any) {
-	basePrint("DEBUG", message, args...)
+	defaultLogger.D(message, args...)
 }
 
 // 普通打印
 func P(message string, args ...any) {
-	basePrint("PRINT", message, args...)
+	defaultLogger.P(message, args...)
 }
@@ -0,0 +1,120 @@
+package logger
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/shi-yunsheng/gostar/date"
+)
+
+func TestConcurrentConfig(t *testing.T) {
+	l := New()
+	defer l.Close()
+	l.SetSavePath(t.TempDir())
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := range 200 {
+			if i%2 == 0 {
+				l.EnableSave()
+				l.DisablePrint()
+				l.SetMaxSingleLogFileSize("1KB")
+			} else {
+				l.EnablePrint()
+				l.DisableSave()
+				l.SetMaxSingleLogFileSize("")
+			}
+			l.SetMaxSaveDays("1d")
+			l.SetLogFormat("[%s] [%s] %s")
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := range 200 {
+			l.I("message %d", i)
+		}
+	}()
+	wg.Wait()
+}
+
+func TestLogAfterClose(t *testing.T) {
+	dir := t.TempDir()
+	l := New()
+	l.SetSavePath(dir)
+	l.EnableSave()
+	l.DisablePrint()
+	l.I("before close")
+	l.Close()
+
+	data, err := os.ReadFile(filepath.Join(dir, date.GetToday(date.FORMAT_DATE)+".log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "before close") {
+		t.Fatalf("log file = %q, want it to contain %q", data, "before close")
+	}
+
+	// 关闭后的日志同步写入标准错误
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stderr := os.Stderr
+	os.Stderr = w
+	l.E("after close")
+	os.Stderr = stderr
+	w.Close()
+	output, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(output), "after close") {
+		t.Fatalf("stderr = %q, want it to contain %q", output, "after close")
+	}
+}
+
+func TestAutoDeleteStopsOnClose(t *testing.T) {
+	dir := t.TempDir()
+	expired := filepath.Join(dir, "2000-01-01.log")
+	if err := os.WriteFile(expired, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	old := time.Now().AddDate(0, 0, -10)
+	if err := os.Chtimes(expired, old, old); err != nil {
+		t.Fatal(err)
+	}
+
+	l := New()
+	l.DisablePrint()
+	l.SetSavePath(dir)
+	l.SetMaxSaveDays("1d")
+	l.EnableAutoDelete()
+	// Close等待清理协程退出，协程未停止时测试会超时
+	defer l.Close()
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		if _, err := os.Stat(expired); os.IsNotExist(err) {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("expired log file not deleted")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// 无效的保存天数不会导致panic
+	invalid := New()
+	invalid.DisablePrint()
+	invalid.SetSavePath(t.TempDir())
+	invalid.SetMaxSaveDays("abc")
+	invalid.EnableAutoDelete()
+	invalid.Close()
+}
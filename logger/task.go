@@ -1,6 +1,7 @@
 package logger
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
 	"time"
@@ -8,40 +9,58 @@ import (
 	"github.com/shi-yunsheng/gostar/date"
 )
 
-// 自动删除日志
-func autoDeleteLogs() {
-	if !enableAutoDelete {
-		return
-	}
+// 保存天数无效时重新读取配置的间隔
+const autoDeleteRetryInterval = time.Hour
 
-	duration, err := date.ParseTimeDuration(maxSaveDays)
-	if err != nil {
-		panic(err)
-	}
+// 自动删除日志，每个周期重新读取保存天数，禁用后暂停清理，日志器关闭后退出
+func (l *Logger) autoDeleteLogs() {
+	defer l.wg.Done()
 
 	for {
-		// 获取过期时间
-		expiredTime := time.Now().Add(-duration)
+		l.mu.RLock()
+		maxSaveDays, enableAutoDelete, savePath := l.maxSaveDays, l.enableAutoDelete, l.savePath
+		l.mu.RUnlock()
 
-		filepath.Walk(savePath, func(path string, info os.FileInfo, err error) error {
-			if err != nil {
-				return nil
-			}
+		duration, err := date.ParseTimeDuration(maxSaveDays)
+		if err == nil && duration <= 0 {
+			err = errors.New("must be positive")
+		}
+		if err != nil {
+			// 保存天数无效时不删除日志，等待配置修改后重试
+			l.E("Invalid max save days %q for log auto delete: %v", maxSaveDays, err)
+			duration = autoDeleteRetryInterval
+		} else if enableAutoDelete {
+			deleteExpiredLogs(savePath, time.Now().Add(-duration))
+		}
 
-			if !info.IsDir() && filepath.Ext(path) == ".log" {
-				fileName := filepath.Base(path)
-				if isLogFile(fileName) {
-					// 检查文件是否过期
-					if info.ModTime().Before(expiredTime) {
-						// 删除过期日志文件，忽略错误
-						os.Remove(path)
-					}
-				}
-			}
+		timer := time.NewTimer(duration)
+		select {
+		case <-l.done:
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+	}
+}
 
+// 删除保存路径下修改时间早于expiredTime的日志文件
+func deleteExpiredLogs(savePath string, expiredTime time.Time) {
+	filepath.Walk(savePath, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
 			return nil
-		})
+		}
 
-		time.Sleep(duration)
-	}
+		if !info.IsDir() && filepath.Ext(path) == ".log" {
+			fileName := filepath.Base(path)
+			if isLogFile(fileName) {
+				// 检查文件是否过期
+				if info.ModTime().Before(expiredTime) {
+					// 删除过期日志文件，忽略错误
+					os.Remove(path)
+				}
+			}
+		}
+
+		return nil
+	})
 }
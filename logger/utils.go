@@ -21,11 +21,11 @@ func isLogFile(fileName string) bool {
 	return matched
 }
 
-// 最后一个日志文件索引
-var lastLogFileIndex int = -1
-
 // 保存日志
-func saveLog(message string) {
+func (l *Logger) saveLog(message string) {
+	l.mu.RLock()
+	enableSave, savePath, maxLogSize := l.enableSave, l.savePath, l.maxLogSize
+	l.mu.RUnlock()
 	if !enableSave {
 		return
 	}
@@ -40,14 +40,14 @@ func saveLog(message string) {
 	if dirInfo, err := os.Stat(utils.JoinPath(savePath, dateDir)); os.IsNotExist(err) {
 		os.MkdirAll(utils.JoinPath(savePath, dateDir), os.ModePerm)
 		// 确保每次新目录创建时，lastLogFileIndex 为 -1
-		lastLogFileIndex = -1
+		l.lastLogFileIndex = -1
 	} else if !dirInfo.IsDir() {
 		panic("log save path is not a directory")
 	}
 
 	var logFilename string
 	if maxLogSize > 0 {
-		if lastLogFileIndex == -1 {
+		if l.lastLogFileIndex == -1 {
 			files, _ := os.ReadDir(utils.JoinPath(savePath, dateDir))
 
 			logFileCount := 0
@@ -57,15 +57,15 @@ func saveLog(message string) {
 				}
 			}
 
-			lastLogFileIndex = logFileCount
+			l.lastLogFileIndex = logFileCount
 		}
 		// 如果文件大小超过最大大小，则创建新的日志分片
-		fileInfo, err := os.Stat(utils.JoinPath(savePath, dateDir, fmt.Sprintf("%s.%d.log", currentDate, lastLogFileIndex)))
+		fileInfo, err := os.Stat(utils.JoinPath(savePath, dateDir, fmt.Sprintf("%s.%d.log", currentDate, l.lastLogFileIndex)))
 		if err != nil || fileInfo.Size() > maxLogSize {
-			lastLogFileIndex++
+			l.lastLogFileIndex++
 		}
 
-		logFilename = utils.JoinPath(savePath, dateDir, fmt.Sprintf("%s.%d.log", currentDate, lastLogFileIndex))
+		logFilename = utils.JoinPath(savePath, dateDir, fmt.Sprintf("%s.%d.log", currentDate, l.lastLogFileIndex))
 	} else {
 		logFilename = utils.JoinPath(savePath, dateDir, currentDate+".log")
 	}
@@ -6,25 +6,43 @@ import (
 	"gorm.io/gorm"
 )
 
+// 绑定到指定数据库连接的模型操作，用于非默认注册表（如多实例、测试应用）中的数据库
+// 例如：model.For[User](app.DB()).Insert(data)
+func For[T any](db *DBClient) *Repo[T] {
+	if db == nil {
+		panic("database not initialized")
+	}
+	return &Repo[T]{db: db}
+}
+
 // 插入方法
 func Insert[T any](data map[string]any, dbName ...string) error {
+	return For[T](getDBClient(dbName...)).Insert(data)
+}
+
+// 插入方法
+func (r *Repo[T]) Insert(data map[string]any) error {
 	model, err := parseData[T](data)
 	if err != nil {
 		return err
 	}
 
-	db := getDBClient(dbName...)
-	return db.db.Create(&model).Error
+	return r.db.db.Create(&model).Error
 }
 
 // 更新方法
 func Update[T any](queryConditions map[string]any, data map[string]any, dbName ...string) error {
+	return For[T](getDBClient(dbName...)).Update(queryConditions, data)
+}
+
+// 更新方法
+func (r *Repo[T]) Update(queryConditions map[string]any, data map[string]any) error {
 	model, err := parseData[T](data)
 	if err != nil {
 		return err
 	}
 
-	query, err := buildBaseQuery[T](queryConditions, dbName...)
+	query, err := buildBaseQuery[T](r.db, queryConditions)
 	if err != nil {
 		return err
 	}
@@ -34,7 +52,12 @@ func Update[T any](queryConditions map[string]any, data map[string]any, dbName .
 
 // 删除方法
 func Delete[T any](queryConditions map[string]any, isHardDelete bool, dbName ...string) error {
-	query, err := buildBaseQuery[T](queryConditions, dbName...)
+	return For[T](getDBClient(dbName...)).Delete(queryConditions, isHardDelete)
+}
+
+// 删除方法
+func (r *Repo[T]) Delete(queryConditions map[string]any, isHardDelete bool) error {
+	query, err := buildBaseQuery[T](r.db, queryConditions)
 	if err != nil {
 		return err
 	}
@@ -51,7 +74,12 @@ func Delete[T any](queryConditions map[string]any, isHardDelete bool, dbName ...
 
 // 查询方法
 func Query[T any](queryConditions map[string]any, dbName ...string) ([]T, error) {
-	query, err := buildBaseQuery[T](queryConditions, dbName...)
+	return For[T](getDBClient(dbName...)).Query(queryConditions)
+}
+
+// 查询方法
+func (r *Repo[T]) Query(queryConditions map[string]any) ([]T, error) {
+	query, err := buildBaseQuery[T](r.db, queryConditions)
 	if err != nil {
 		return nil, err
 	}
@@ -64,6 +92,11 @@ func Query[T any](queryConditions map[string]any, dbName ...string) ([]T, error)
 
 // 批量插入
 func BatchInsert[T any](data []map[string]any, dbName ...string) error {
+	return For[T](getDBClient(dbName...)).BatchInsert(data)
+}
+
+// 批量插入
+func (r *Repo[T]) BatchInsert(data []map[string]any) error {
 	if len(data) == 0 {
 		return fmt.Errorf("batch insert data cannot be empty")
 	}
@@ -79,15 +112,18 @@ func BatchInsert[T any](data []map[string]any, dbName ...string) error {
 		models = append(models, model)
 	}
 	// 分批插入
-	db := getDBClient(dbName...)
-
-	return db.db.CreateInBatches(&models, size).Error
+	return r.db.db.CreateInBatches(&models, size).Error
 }
 
 // 查询第一条
 func First[T any](queryConditions map[string]any, dbName ...string) (T, error) {
+	return For[T](getDBClient(dbName...)).First(queryConditions)
+}
+
+// 查询第一条
+func (r *Repo[T]) First(queryConditions map[string]any) (T, error) {
 	var result T
-	query, err := buildBaseQuery[T](queryConditions, dbName...)
+	query, err := buildBaseQuery[T](r.db, queryConditions)
 	if err != nil {
 		return result, err
 	}
@@ -99,21 +135,25 @@ func First[T any](queryConditions map[string]any, dbName ...string) (T, error) {
 
 // 计数方法
 func Count[T any](queryConditions map[string]any, dbName ...string) (int64, error) {
+	return For[T](getDBClient(dbName...)).Count(queryConditions)
+}
+
+// 计数方法
+func (r *Repo[T]) Count(queryConditions map[string]any) (int64, error) {
 	var query *gorm.DB
 	var err error
 
 	if len(queryConditions) > 0 {
-		query, err = buildBaseQuery[T](queryConditions, dbName...)
+		query, err = buildBaseQuery[T](r.db, queryConditions)
 		if err != nil {
 			return 0, err
 		}
 	} else {
-		model, e := parseModelWithCache[T](dbName...)
+		model, e := parseModelWithCache[T](r.db)
 		if e != nil {
 			return 0, e
 		}
-		db := getDBClient(dbName...)
-		query = db.db.Model(model)
+		query = r.db.db.Model(model)
 	}
 
 	var count int64
@@ -124,7 +164,12 @@ func Count[T any](queryConditions map[string]any, dbName ...string) (int64, erro
 
 // 软删除查询
 func QueryWithDeleted[T any](queryConditions map[string]any, includeDeleted bool, dbName ...string) ([]T, error) {
-	query, err := buildBaseQuery[T](queryConditions, dbName...)
+	return For[T](getDBClient(dbName...)).QueryWithDeleted(queryConditions, includeDeleted)
+}
+
+// 软删除查询
+func (r *Repo[T]) QueryWithDeleted(queryConditions map[string]any, includeDeleted bool) ([]T, error) {
+	query, err := buildBaseQuery[T](r.db, queryConditions)
 	if err != nil {
 		return nil, err
 	}
@@ -2,8 +2,11 @@ package model
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"maps"
 	"reflect"
+	"slices"
 	"strings"
 	"sync"
 	"time"
@@ -47,31 +50,64 @@ type DBClient struct {
 	prefix       string
 	models       map[string]any    // 模型类型名 -> 模型实例
 	tableNameMap map[string]string // 模型类型名 -> 实际表名
+	modelCache   sync.Map          // 模型类型 -> 已注册的模型实例
 }
 
-var (
-	dbMap      map[string]*DBClient
-	dbMapLock  sync.RWMutex
-	modelCache = sync.Map{} // 模型缓存
-)
+// 支持的数据库驱动
+var supportedDrivers = []string{"mysql", "postgres", "sqlite", "mongo"}
 
-var debug = false
+// 判断是否为支持的数据库驱动
+func IsSupportedDriver(driver string) bool {
+	return slices.Contains(supportedDrivers, driver)
+}
 
-// 开启调试模式
-func EnableDebug() {
-	debug = true
+// 获取支持的数据库驱动
+func SupportedDrivers() []string {
+	return slices.Clone(supportedDrivers)
 }
 
-// 初始化数据库
+// 初始化默认注册表的数据库，失败时panic
 func InitDB(config map[string]DBConfig) {
+	defaultRegistry.InitDB(config)
+}
+
+// 初始化默认注册表的数据库，失败时返回错误
+func InitDBE(config map[string]DBConfig) error {
+	return defaultRegistry.InitDBE(config)
+}
+
+// 获取默认注册表的数据库
+func GetDB(name ...string) *DBClient {
+	return defaultRegistry.GetDB(name...)
+}
+
+// 关闭默认注册表的所有数据库连接
+func CloseDB() error {
+	return defaultRegistry.CloseDB()
+}
+
+// 初始化数据库，失败时panic
+func (r *Registry) InitDB(config map[string]DBConfig) {
+	if err := r.InitDBE(config); err != nil {
+		panic(err.Error())
+	}
+}
+
+// 初始化数据库，失败时关闭本次已打开的连接并返回错误
+func (r *Registry) InitDBE(config map[string]DBConfig) (err error) {
 	if len(config) == 0 {
-		return
+		return nil
 	}
 
-	dbMapLock.Lock()
-	defer dbMapLock.Unlock()
+	r.dbMapLock.Lock()
+	defer r.dbMapLock.Unlock()
 
-	dbMap = make(map[string]*DBClient)
+	r.dbMap = make(map[string]*DBClient)
+	defer func() {
+		if err != nil {
+			err = errors.Join(err, r.closeDB())
+		}
+	}()
 
 	for name, config := range config {
 		switch config.Driver {
@@ -85,7 +121,7 @@ func InitDB(config map[string]DBConfig) {
 			var err error
 
 			conf := &gorm.Config{}
-			if debug {
+			if r.debug {
 				conf.Logger = logger.Default.LogMode(logger.Info)
 			} else {
 				conf.Logger = logger.Default.LogMode(logger.Silent)
@@ -101,7 +137,7 @@ func InitDB(config map[string]DBConfig) {
 			}
 
 			if err != nil {
-				panic("failed to connect to database " + name + ": " + err.Error())
+				return fmt.Errorf("failed to connect to database %s: %w", name, err)
 			}
 			// 设置表前缀
 			if config.TablePrefix != "" {
@@ -110,7 +146,7 @@ func InitDB(config map[string]DBConfig) {
 				}
 			}
 
-			dbMap[name] = &DBClient{
+			r.dbMap[name] = &DBClient{
 				db:           db,
 				prefix:       config.TablePrefix,
 				models:       make(map[string]any),
@@ -124,21 +160,22 @@ func InitDB(config map[string]DBConfig) {
 
 			client, err := mongo.Connect(context.TODO(), options.Client().ApplyURI(config.DSN))
 			if err != nil {
-				panic("failed to connect to MongoDB " + name + ": " + err.Error())
+				return fmt.Errorf("failed to connect to MongoDB %s: %w", name, err)
 			}
 			// 测试连接
 			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 			defer cancel()
 			err = client.Ping(ctx, nil)
 			if err != nil {
-				panic("failed to ping MongoDB " + name + ": " + err.Error())
+				_ = client.Disconnect(ctx)
+				return fmt.Errorf("failed to ping MongoDB %s: %w", name, err)
 			}
 			// 获取数据库
 			databaseName := config.Database
 			if databaseName == "" {
 				databaseName = fmt.Sprintf("db%s", config.Database)
 			}
-			dbMap[name] = &DBClient{
+			r.dbMap[name] = &DBClient{
 				mongo:        client.Database(databaseName),
 				prefix:       config.TablePrefix,
 				models:       make(map[string]any),
@@ -146,9 +183,11 @@ func InitDB(config map[string]DBConfig) {
 			}
 
 		default:
-			panic("unsupported database driver: " + config.Driver)
+			return fmt.Errorf("unsupported database driver: %s", config.Driver)
 		}
 	}
+
+	return nil
 }
 
 // 根据数据库类型生成对应的DSN
@@ -178,23 +217,94 @@ func generateMongoDSN(config DBConfig) string {
 }
 
 // 获取数据库
-func GetDB(name ...string) *DBClient {
-	if dbMap == nil {
+func (r *Registry) GetDB(name ...string) *DBClient {
+	r.dbMapLock.RLock()
+	defer r.dbMapLock.RUnlock()
+
+	if r.dbMap == nil {
 		panic("database not initialized")
 	}
 
-	dbMapLock.RLock()
-	defer dbMapLock.RUnlock()
-
 	if len(name) == 0 {
-		return dbMap["default"]
+		return r.dbMap["default"]
 	}
 
-	if _, ok := dbMap[name[0]]; !ok {
+	if _, ok := r.dbMap[name[0]]; !ok {
 		panic("database " + name[0] + " not found")
 	}
 
-	return dbMap[name[0]]
+	return r.dbMap[name[0]]
+}
+
+// 获取所有数据库连接名称
+func (r *Registry) DBNames() []string {
+	r.dbMapLock.RLock()
+	defer r.dbMapLock.RUnlock()
+
+	return slices.Sorted(maps.Keys(r.dbMap))
+}
+
+// 关闭所有数据库连接，按连接名称顺序依次关闭
+func (r *Registry) CloseDB() error {
+	r.dbMapLock.Lock()
+	defer r.dbMapLock.Unlock()
+
+	return r.closeDB()
+}
+
+// 关闭所有数据库连接，调用方需持有dbMapLock
+func (r *Registry) closeDB() error {
+	var errs []error
+	for _, name := range slices.Sorted(maps.Keys(r.dbMap)) {
+		if err := r.dbMap[name].Close(); err != nil {
+			errs = append(errs, fmt.Errorf("close database %s failed: %w", name, err))
+		}
+	}
+	r.dbMap = nil
+
+	return errors.Join(errs...)
+}
+
+// 获取gorm数据库实例，MongoDB连接返回nil
+func (d *DBClient) Gorm() *gorm.DB {
+	return d.db
+}
+
+// 获取MongoDB数据库实例，非MongoDB连接返回nil
+func (d *DBClient) Mongo() *mongo.Database {
+	return d.mongo
+}
+
+// 关闭数据库连接
+func (d *DBClient) Close() error {
+	if d.mongo != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		return d.mongo.Client().Disconnect(ctx)
+	}
+	if d.db != nil {
+		sqlDB, err := d.db.DB()
+		if err != nil {
+			return err
+		}
+		return sqlDB.Close()
+	}
+	return nil
+}
+
+// 检查数据库连接是否可用
+func (d *DBClient) Ping(ctx context.Context) error {
+	if d.mongo != nil {
+		return d.mongo.Client().Ping(ctx, nil)
+	}
+	if d.db != nil {
+		sqlDB, err := d.db.DB()
+		if err != nil {
+			return err
+		}
+		return sqlDB.PingContext(ctx)
+	}
+	return errors.New("database not initialized")
 }
 
 // 自动迁移数据库
@@ -221,6 +331,8 @@ func (d *DBClient) AutoMigrate(models ...any) error {
 		d.models[modelName] = model
 		d.tableNameMap[modelName] = getTableName(model)
 	}
+	// 重新迁移后模型实例可能变化
+	d.modelCache.Clear()
 	return d.db.AutoMigrate(models...)
 }
 
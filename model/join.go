@@ -9,6 +9,11 @@ import (
 
 // 联合查询
 func JoinQuery[T any](params JoinParams, dbName ...string) (any, error) {
+	return For[T](getDBClient(dbName...)).JoinQuery(params)
+}
+
+// 联合查询
+func (r *Repo[T]) JoinQuery(params JoinParams) (any, error) {
 	if len(params.Models) == 0 {
 		return nil, fmt.Errorf("models list cannot be empty")
 	}
@@ -17,10 +22,10 @@ func JoinQuery[T any](params JoinParams, dbName ...string) (any, error) {
 		return nil, fmt.Errorf("join conditions should match the number of models excluding the main table")
 	}
 
-	db := getDBClient(dbName...)
+	db := r.db
 	query := db.db
 	tablePrefix := db.prefix
-	joinConditions := parseJoinConditions(params.JoinConditions, db.tableNameMap, dbName...)
+	joinConditions := parseJoinConditions(params.JoinConditions, db.tableNameMap, tablePrefix)
 	// 将所有模型转换为结构体类型并获取表名
 	for i, model := range params.Models {
 		modelType := reflect.TypeOf(model)
@@ -168,13 +168,17 @@ func formatGroupByField(field string, tableNameMap map[string]string) string {
 
 // 分页查询
 func Pagination[T any](params PageParams, dbName ...string) (PageResult[T], error) {
-	model, err := parseModelWithCache[T](dbName...)
+	return For[T](getDBClient(dbName...)).Pagination(params)
+}
+
+// 分页查询
+func (r *Repo[T]) Pagination(params PageParams) (PageResult[T], error) {
+	model, err := parseModelWithCache[T](r.db)
 	if err != nil {
 		return PageResult[T]{}, err
 	}
 
-	db := getDBClient(dbName...)
-	query := db.db.Model(model)
+	query := r.db.db.Model(model)
 
 	return parsePager[T](&params, query, nil)
 }
@@ -20,8 +20,8 @@ func getDBClient(dbName ...string) *DBClient {
 	return GetDB()
 }
 
-// 模型解析并缓存
-func parseModelWithCache[T any](dbName ...string) (*T, error) {
+// 模型解析并缓存，缓存属于数据库连接，不同注册表中的同名连接互不影响
+func parseModelWithCache[T any](dbClient *DBClient) (*T, error) {
 	var model *T
 	t := reflect.TypeOf(model)
 
@@ -29,12 +29,11 @@ func parseModelWithCache[T any](dbName ...string) (*T, error) {
 		t = t.Elem()
 	}
 	// 尝试从缓存获取
-	if cached, ok := modelCache.Load(t); ok {
+	if cached, ok := dbClient.modelCache.Load(t); ok {
 		return cached.(*T), nil
 	}
 	// 根据名称获取模型
 	modelName := utils.CamelToSnake(t.Name())
-	dbClient := getDBClient(dbName...)
 	if dbClient.models == nil {
 		return nil, fmt.Errorf("no models registered, please call AutoMigrate first")
 	}
@@ -43,7 +42,7 @@ func parseModelWithCache[T any](dbName ...string) (*T, error) {
 		return nil, fmt.Errorf("model [%s] not found", modelName)
 	}
 	// 存入缓存
-	modelCache.Store(t, model)
+	dbClient.modelCache.Store(t, model)
 	return model, nil
 }
 
@@ -202,13 +201,12 @@ func formatSelectField(field string, tableNameMap map[string]string, tablePrefix
 }
 
 // 构建基础查询
-func buildBaseQuery[T any](queryConditions map[string]any, dbName ...string) (*gorm.DB, error) {
-	model, err := parseModelWithCache[T](dbName...)
+func buildBaseQuery[T any](dbClient *DBClient, queryConditions map[string]any) (*gorm.DB, error) {
+	model, err := parseModelWithCache[T](dbClient)
 	if err != nil {
 		return nil, err
 	}
 
-	dbClient := getDBClient(dbName...)
 	// 单表查询不需要表名映射，传递 nil
 	query, err := parseQueryConditions(queryConditions, nil, dbClient.db)
 	if err != nil {
@@ -393,10 +391,8 @@ func parseQueryConditions(queryConditions map[string]any, tableNameMap map[strin
 }
 
 // 解析连接条件
-func parseJoinConditions(joinConditions []string, tableNameMap map[string]string, dbName ...string) []JoinCondition {
+func parseJoinConditions(joinConditions []string, tableNameMap map[string]string, tablePrefix string) []JoinCondition {
 	result := make([]JoinCondition, 0, len(joinConditions))
-	// 获取表前缀
-	tablePrefix := getDBClient(dbName...).prefix
 
 	// 支持的连接类型
 	joinTypes := map[string]bool{
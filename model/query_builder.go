@@ -6,14 +6,18 @@ import (
 
 // 查询构建器模式
 func NewQueryBuilder[T any](dbName ...string) (*QueryBuilder[T], error) {
-	model, err := parseModelWithCache[T](dbName...)
+	return For[T](getDBClient(dbName...)).QueryBuilder()
+}
+
+// 查询构建器模式
+func (r *Repo[T]) QueryBuilder() (*QueryBuilder[T], error) {
+	model, err := parseModelWithCache[T](r.db)
 	if err != nil {
 		return nil, err
 	}
 
-	db := getDBClient(dbName...)
 	return &QueryBuilder[T]{
-		db:         db.db.Model(model),
+		db:         r.db.db.Model(model),
 		model:      model,
 		conditions: make([]QueryCondition, 0),
 		orderBy:    make([]string, 0),
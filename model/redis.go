@@ -1,9 +1,13 @@
 package model
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
 	"strconv"
 	"strings"
-	"sync"
 	"time"
 
 	"github.com/shi-yunsheng/gostar/date"
@@ -33,34 +37,61 @@ type RedisClient struct {
 	prefix string
 }
 
-var (
-	redisMap     map[string]*RedisClient
-	redisMapLock sync.RWMutex
-)
-
-// 初始化Redis
+// 初始化默认注册表的Redis，失败时panic
 func InitRedis(config map[string]RedisConfig) {
+	defaultRegistry.InitRedis(config)
+}
+
+// 初始化默认注册表的Redis，失败时返回错误
+func InitRedisE(config map[string]RedisConfig) error {
+	return defaultRegistry.InitRedisE(config)
+}
+
+// 获取默认注册表的Redis
+func GetRedis(name ...string) *RedisClient {
+	return defaultRegistry.GetRedis(name...)
+}
+
+// 关闭默认注册表的所有Redis连接
+func CloseRedis() error {
+	return defaultRegistry.CloseRedis()
+}
+
+// 初始化Redis，失败时panic
+func (r *Registry) InitRedis(config map[string]RedisConfig) {
+	if err := r.InitRedisE(config); err != nil {
+		panic(err.Error())
+	}
+}
+
+// 初始化Redis，失败时关闭本次已创建的连接并返回错误
+func (r *Registry) InitRedisE(config map[string]RedisConfig) (err error) {
 	if len(config) == 0 {
-		return
+		return nil
 	}
 
-	redisMapLock.Lock()
-	defer redisMapLock.Unlock()
+	r.redisMapLock.Lock()
+	defer r.redisMapLock.Unlock()
 
-	redisMap = make(map[string]*RedisClient)
+	r.redisMap = make(map[string]*RedisClient)
+	defer func() {
+		if err != nil {
+			err = errors.Join(err, r.closeRedis())
+		}
+	}()
 
 	for name, config := range config {
 		if strings.TrimSpace(config.DSN) != "" {
 			opt, err := redis.ParseURL(config.DSN)
 			if err != nil {
-				panic("parse redis dsn failed: " + err.Error())
+				return fmt.Errorf("parse redis %s dsn failed: %w", name, err)
 			}
-			redisMap[name] = &RedisClient{
+			r.redisMap[name] = &RedisClient{
 				client: redis.NewClient(opt),
 				prefix: config.Prefix,
 			}
 		} else {
-			redisMap[name] = &RedisClient{
+			r.redisMap[name] = &RedisClient{
 				client: redis.NewClient(&redis.Options{
 					Addr:     config.Host + ":" + strconv.Itoa(config.Port),
 					Password: config.Password,
@@ -70,26 +101,72 @@ func InitRedis(config map[string]RedisConfig) {
 			}
 		}
 	}
+
+	return nil
 }
 
 // 获取Redis
-func GetRedis(name ...string) *RedisClient {
-	if redisMap == nil {
+func (r *Registry) GetRedis(name ...string) *RedisClient {
+	r.redisMapLock.RLock()
+	defer r.redisMapLock.RUnlock()
+
+	if r.redisMap == nil {
 		panic("redis not initialized")
 	}
 
-	redisMapLock.RLock()
-	defer redisMapLock.RUnlock()
-
 	if len(name) == 0 {
-		return redisMap["default"]
+		return r.redisMap["default"]
 	}
 
-	if _, ok := redisMap[name[0]]; !ok {
+	if _, ok := r.redisMap[name[0]]; !ok {
 		panic("redis " + name[0] + " not found")
 	}
 
-	return redisMap[name[0]]
+	return r.redisMap[name[0]]
+}
+
+// 获取所有Redis连接名称
+func (r *Registry) RedisNames() []string {
+	r.redisMapLock.RLock()
+	defer r.redisMapLock.RUnlock()
+
+	return slices.Sorted(maps.Keys(r.redisMap))
+}
+
+// 关闭所有Redis连接，按连接名称顺序依次关闭
+func (r *Registry) CloseRedis() error {
+	r.redisMapLock.Lock()
+	defer r.redisMapLock.Unlock()
+
+	return r.closeRedis()
+}
+
+// 关闭所有Redis连接，调用方需持有redisMapLock
+func (r *Registry) closeRedis() error {
+	var errs []error
+	for _, name := range slices.Sorted(maps.Keys(r.redisMap)) {
+		if err := r.redisMap[name].Close(); err != nil {
+			errs = append(errs, fmt.Errorf("close redis %s failed: %w", name, err))
+		}
+	}
+	r.redisMap = nil
+
+	return errors.Join(errs...)
+}
+
+// 获取Redis客户端
+func (r *RedisClient) Client() *redis.Client {
+	return r.client
+}
+
+// 关闭Redis连接
+func (r *RedisClient) Close() error {
+	return r.client.Close()
+}
+
+// 检查Redis连接是否可用
+func (r *RedisClient) Ping(ctx context.Context) error {
+	return r.client.WithContext(ctx).Ping().Err()
 }
 
 // 从Redis中获取值
@@ -0,0 +1,37 @@
+package model
+
+import "sync"
+
+// 连接注册表，保存数据库和Redis连接，每个GoStar实例拥有独立的注册表
+// 包级别的InitDB、GetDB、InitRedis、GetRedis等函数使用默认注册表
+type Registry struct {
+	dbMap        map[string]*DBClient
+	dbMapLock    sync.RWMutex
+	redisMap     map[string]*RedisClient
+	redisMapLock sync.RWMutex
+	// 调试模式
+	debug bool
+}
+
+// 默认注册表
+var defaultRegistry = NewRegistry()
+
+// 新建连接注册表
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// 获取默认注册表
+func DefaultRegistry() *Registry {
+	return defaultRegistry
+}
+
+// 开启调试模式
+func (r *Registry) EnableDebug() {
+	r.debug = true
+}
+
+// 开启默认注册表的调试模式
+func EnableDebug() {
+	defaultRegistry.EnableDebug()
+}
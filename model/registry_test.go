@@ -0,0 +1,29 @@
+package model
+
+import "testing"
+
+func TestInitFailureClosesConnections(t *testing.T) {
+	r := NewRegistry()
+	err := r.InitDBE(map[string]DBConfig{
+		"a": {Driver: "sqlite", DSN: "file:registry_test_a?mode=memory&cache=shared"},
+		"b": {Driver: "sqlite", DSN: "file:registry_test_b?mode=memory&cache=shared"},
+		"c": {Driver: "oracle"},
+	})
+	if err == nil {
+		t.Fatal("InitDBE: got nil error for unsupported driver")
+	}
+	if names := r.DBNames(); len(names) != 0 {
+		t.Fatalf("DBNames after failed init = %v, want none", names)
+	}
+
+	err = r.InitRedisE(map[string]RedisConfig{
+		"a": {Host: "127.0.0.1", Port: 6379},
+		"b": {DSN: "invalid://"},
+	})
+	if err == nil {
+		t.Fatal("InitRedisE: got nil error for invalid dsn")
+	}
+	if names := r.RedisNames(); len(names) != 0 {
+		t.Fatalf("RedisNames after failed init = %v, want none", names)
+	}
+}
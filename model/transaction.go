@@ -6,9 +6,13 @@ import (
 
 // 事务支持
 func WithTransaction[T any](fn func(*gorm.DB) (T, error), txConfig *TxConfig, dbName ...string) (T, error) {
+	return WithTransactionOn(getDBClient(dbName...), fn, txConfig)
+}
+
+// 在指定数据库连接上执行事务，用于非默认注册表中的数据库，如：model.WithTransactionOn(app.DB(), fn, nil)
+func WithTransactionOn[T any](dbClient *DBClient, fn func(*gorm.DB) (T, error), txConfig *TxConfig) (T, error) {
 	var result T
 
-	dbClient := getDBClient(dbName...)
 	db := dbClient.db
 
 	// 设置事务隔离级别
@@ -55,6 +55,11 @@ type TxConfig struct {
 	Timeout time.Duration
 }
 
+// 模型操作，通过For创建，所有操作使用绑定的数据库连接
+type Repo[T any] struct {
+	db *DBClient
+}
+
 // 查询构建器结构
 type QueryBuilder[T any] struct {
 	db         *gorm.DB
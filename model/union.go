@@ -29,6 +29,11 @@ type UnionParams struct {
 // UnionQuery 执行 UNION 查询，合并多个表的数据
 // T 为返回结果的类型，通常是一个 DTO 结构
 func UnionQuery[T any](params UnionParams, dbName ...string) (any, error) {
+	return For[T](getDBClient(dbName...)).UnionQuery(params)
+}
+
+// UnionQuery 在绑定的数据库连接上执行 UNION 查询，用法同包级别的 UnionQuery
+func (r *Repo[T]) UnionQuery(params UnionParams) (any, error) {
 	if len(params.Tables) < 2 {
 		return nil, fmt.Errorf("union query requires at least 2 tables")
 	}
@@ -47,7 +52,7 @@ func UnionQuery[T any](params UnionParams, dbName ...string) (any, error) {
 		}
 	}
 
-	db := getDBClient(dbName...)
+	db := r.db
 	tablePrefix := db.prefix
 
 	// 构建 UNION SQL
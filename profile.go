@@ -0,0 +1,61 @@
+package gostar
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// 指定配置环境的环境变量，如：GOSTAR_PROFILE=prod 会在config.yaml之后合并config.prod.yaml
+	envProfile = "GOSTAR_PROFILE"
+	// 禁止自动生成默认配置文件的环境变量，值为true时生效
+	envDisableConfigGeneration = "GOSTAR_DISABLE_CONFIG_GENERATION"
+)
+
+// 是否禁止自动生成默认配置文件
+var disableConfigGeneration = false
+
+// 禁止在配置文件不存在时自动生成默认配置文件，需在New之前调用
+// 禁止后如果配置文件不存在，New将直接报错，建议在生产环境中使用
+func DisableConfigGeneration() {
+	disableConfigGeneration = true
+}
+
+// 判断是否允许自动生成默认配置文件
+func isConfigGenerationDisabled() bool {
+	if disableConfigGeneration {
+		return true
+	}
+	return strings.EqualFold(strings.TrimSpace(os.Getenv(envDisableConfigGeneration)), "true")
+}
+
+// 获取配置环境，参数优先，其次使用环境变量GOSTAR_PROFILE
+func getProfile(profile string) string {
+	if strings.TrimSpace(profile) != "" {
+		return strings.TrimSpace(profile)
+	}
+	return strings.TrimSpace(os.Getenv(envProfile))
+}
+
+// 获取环境配置文件名，如：config.yaml + prod => config.prod.yaml，config.json + prod => config.prod.json
+func getProfileConfigName(configName string, profile string) string {
+	ext := filepath.Ext(configName)
+	return strings.TrimSuffix(configName, ext) + "." + profile + ext
+}
+
+// 深度合并配置，src中的值覆盖dst，两边都是映射时递归合并，src中的空值（如只写了database:）不会覆盖dst
+func mergeConfig(dst map[string]any, src map[string]any) {
+	for key, srcVal := range src {
+		if srcVal == nil {
+			continue
+		}
+		srcMap, srcIsMap := srcVal.(map[string]any)
+		dstMap, dstIsMap := dst[key].(map[string]any)
+		if srcIsMap && dstIsMap {
+			mergeConfig(dstMap, srcMap)
+			continue
+		}
+		dst[key] = srcVal
+	}
+}
@@ -0,0 +1,28 @@
+package gostar
+
+import (
+	"testing"
+	"testing/fstest"
+)
+
+func TestProfileMerge(t *testing.T) {
+	fsys := fstest.MapFS{
+		"config.yaml":      {Data: []byte("bind: 127.0.0.1:8000\ndebug: true\ndatabase:\n  default:\n    driver: sqlite\n    database: app\napp:\n  name: base\n  mode: dev\n")},
+		"config.prod.yaml": {Data: []byte("debug: false\ndatabase:\napp:\n  mode: prod\n")},
+	}
+	c, err := loadConfig(&configReader{fsys: fsys}, "config.yaml", "prod")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if c.Debug {
+		t.Error("debug = true, want false from profile")
+	}
+	if db, ok := c.Database["default"]; !ok || db.Driver != "sqlite" {
+		t.Errorf("database.default = %+v, want base config kept for empty profile section", db)
+	}
+	app, _ := c.Custom["app"].(map[string]any)
+	if app["name"] != "base" || app["mode"] != "prod" {
+		t.Errorf("app = %v, want name from base and mode from profile", app)
+	}
+}
@@ -0,0 +1,159 @@
+package gostar
+
+import (
+	"reflect"
+	"strings"
+	"time"
+)
+
+// 配置文件检查间隔
+const configWatchInterval = time.Second
+
+// 配置变更订阅者
+type configListener struct {
+	path     string
+	callback func(oldVal, newVal any)
+}
+
+// 配置文件状态，用于判断文件是否变更
+type configFileState struct {
+	modTime time.Time
+	size    int64
+}
+
+// 订阅配置变更，path支持嵌套键，如："upload.maxsize"、"log.enable_print"，为空时订阅全部配置
+// 配置热加载后，如果path对应的值发生变化，则调用callback，参数为变更前后的值
+func (g *goStar) OnConfigChange(path string, callback func(oldVal, newVal any)) {
+	g.configListenersLock.Lock()
+	defer g.configListenersLock.Unlock()
+
+	g.configListeners = append(g.configListeners, configListener{
+		path:     path,
+		callback: callback,
+	})
+}
+
+// 获取需要监听的配置文件
+func (g *goStar) getWatchFiles() []string {
+	files := []string{g.configName}
+	if g.profile != "" {
+		files = append(files, getProfileConfigName(g.configName, g.profile))
+	}
+	return files
+}
+
+// 获取配置文件状态，文件不存在时返回零值
+func getConfigFileStates(reader *configReader, files []string) []configFileState {
+	states := make([]configFileState, len(files))
+	for i, file := range files {
+		if info, err := reader.stat(file); err == nil {
+			states[i] = configFileState{modTime: info.ModTime(), size: info.Size()}
+		}
+	}
+	return states
+}
+
+// 监听配置文件变更
+func (g *goStar) watchConfig() {
+	g.stopWatchLock.Lock()
+	defer g.stopWatchLock.Unlock()
+	if g.stopWatch != nil {
+		return
+	}
+	// 从io.Reader读取的配置无法重新读取
+	if g.configReader.data != nil {
+		g.logger.W("Hot reload is not supported for config %s loaded from a reader", g.configName)
+		return
+	}
+	g.stopWatch = make(chan struct{})
+
+	files := g.getWatchFiles()
+	states := getConfigFileStates(g.configReader, files)
+
+	go func(stop chan struct{}) {
+		ticker := time.NewTicker(configWatchInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				current := getConfigFileStates(g.configReader, files)
+				if !reflect.DeepEqual(states, current) {
+					states = current
+					g.ReloadConfig()
+				}
+			}
+		}
+	}(g.stopWatch)
+}
+
+// 停止监听配置文件
+func (g *goStar) stopWatchConfig() {
+	g.stopWatchLock.Lock()
+	defer g.stopWatchLock.Unlock()
+	if g.stopWatch == nil {
+		return
+	}
+	close(g.stopWatch)
+	g.stopWatch = nil
+}
+
+// 重新加载配置，解析失败时保留旧配置
+// 重新加载后会应用日志、跨域和语言配置，并通知配置变更订阅者，绑定地址、数据库等配置仍需重启后生效
+func (g *goStar) ReloadConfig() {
+	newConfig, err := loadConfig(g.configReader, g.configName, g.profile)
+	if err == nil {
+		err = newConfig.validate()
+	}
+	if err != nil {
+		g.logger.E("Reload config %s failed, keeping the old config: %v", g.configName, err)
+		return
+	}
+
+	oldConfig := g.config.Swap(newConfig)
+	g.initLog()
+	g.initLang()
+	g.logger.I("Config %s reloaded", g.configName)
+
+	g.notifyConfigChange(oldConfig, newConfig)
+}
+
+// 通知配置变更订阅者
+func (g *goStar) notifyConfigChange(oldConfig, newConfig *config) {
+	g.configListenersLock.RLock()
+	listeners := make([]configListener, len(g.configListeners))
+	copy(listeners, g.configListeners)
+	g.configListenersLock.RUnlock()
+
+	for _, listener := range listeners {
+		oldVal := lookupConfig(oldConfig, listener.path)
+		newVal := lookupConfig(newConfig, listener.path)
+		if reflect.DeepEqual(oldVal, newVal) {
+			continue
+		}
+
+		func() {
+			defer func() {
+				if err := recover(); err != nil {
+					g.logger.E("Config change listener for %q panicked: %v", listener.path, err)
+				}
+			}()
+			listener.callback(oldVal, newVal)
+		}()
+	}
+}
+
+// 按路径查找配置值，框架配置从所有配置中查找，其余从自定义配置中查找
+func lookupConfig(c *config, path string) any {
+	if path == "" {
+		return c.all
+	}
+
+	key, _, _ := strings.Cut(path, ".")
+	if containsString(getStructYamlTags(c), key) {
+		return getConfigValue(c.all, path)
+	}
+	return getConfigValue(c.Custom, path)
+}
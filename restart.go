@@ -0,0 +1,248 @@
+package gostar
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shi-yunsheng/gostar/date"
+)
+
+// 重启相关的环境变量，由旧进程传递给新进程
+const (
+	// 继承的监听器列表，JSON格式
+	envInheritedListeners = "GOSTAR_INHERITED_LISTENERS"
+	// 通知旧进程已就绪的文件描述符
+	envRestartReadyFD = "GOSTAR_RESTART_READY_FD"
+)
+
+// 等待新进程就绪的默认最长时间
+const defaultRestartReadyTimeout = 30 * time.Second
+
+// 重启配置
+type restartConfig struct {
+	// 是否启用收到SIGHUP信号时零停机重启
+	Enable bool `yaml:"enable"`
+	// 等待新进程就绪的最长时间，默认30s，超时后终止新进程并继续使用旧进程
+	ReadyTimeout string `yaml:"ready_timeout"`
+}
+
+// 获取等待新进程就绪的最长时间
+func (c *restartConfig) readyTimeout() time.Duration {
+	if strings.TrimSpace(c.ReadyTimeout) == "" {
+		return defaultRestartReadyTimeout
+	}
+	timeout, err := date.ParseTimeDuration(c.ReadyTimeout)
+	if err != nil || timeout <= 0 {
+		return defaultRestartReadyTimeout
+	}
+	return timeout
+}
+
+// 从旧进程继承的监听器
+type inheritedListener struct {
+	// 文件描述符
+	FD int `json:"fd"`
+	// 监听地址类型
+	Kind string `json:"kind"`
+	// 配置中的绑定地址
+	Address string `json:"address"`
+	// 是否已使用
+	used bool
+}
+
+var (
+	inheritedListeners     []*inheritedListener
+	inheritedListenersOnce sync.Once
+	inheritedListenersLock sync.Mutex
+)
+
+// 获取从旧进程继承的监听器，读取后会清除环境变量，避免被再次继承
+func getInheritedListeners() []*inheritedListener {
+	inheritedListenersOnce.Do(func() {
+		data := os.Getenv(envInheritedListeners)
+		os.Unsetenv(envInheritedListeners)
+		if data == "" {
+			return
+		}
+		if err := json.Unmarshal([]byte(data), &inheritedListeners); err != nil {
+			inheritedListeners = nil
+		}
+	})
+	return inheritedListeners
+}
+
+// 获取与绑定地址对应的继承监听器，没有时返回空列表
+func takeInheritedListeners(kind string, address string) ([]*bindListener, error) {
+	inheritedListenersLock.Lock()
+	defer inheritedListenersLock.Unlock()
+
+	var listeners []*bindListener
+	for _, inherited := range getInheritedListeners() {
+		if inherited.used || inherited.Kind != kind || inherited.Address != address {
+			continue
+		}
+		inherited.used = true
+
+		file := os.NewFile(uintptr(inherited.FD), "inherited:"+address)
+		ln, err := net.FileListener(file)
+		file.Close()
+		if err != nil {
+			closeListeners(listeners)
+			return nil, err
+		}
+		// 由当前进程负责在退出时删除套接字文件
+		if unixLn, ok := ln.(*net.UnixListener); ok {
+			unixLn.SetUnlinkOnClose(true)
+		}
+		listeners = append(listeners, &bindListener{Listener: ln, address: address})
+	}
+	return listeners, nil
+}
+
+// 关闭未使用的继承监听器，如：新版本的配置中已移除的绑定地址
+func closeUnusedInheritedListeners() {
+	inheritedListenersLock.Lock()
+	defer inheritedListenersLock.Unlock()
+
+	for _, inherited := range getInheritedListeners() {
+		if !inherited.used {
+			inherited.used = true
+			if file := os.NewFile(uintptr(inherited.FD), "inherited:"+inherited.Address); file != nil {
+				file.Close()
+			}
+		}
+	}
+}
+
+// 由旧进程重启而来时，通知旧进程已就绪
+func notifyRestartReady() {
+	fd, err := strconv.Atoi(os.Getenv(envRestartReadyFD))
+	os.Unsetenv(envRestartReadyFD)
+	if err != nil {
+		return
+	}
+
+	file := os.NewFile(uintptr(fd), "restart-ready")
+	if file == nil {
+		return
+	}
+	file.Write([]byte{1})
+	file.Close()
+}
+
+// 获取监听器的文件，文件描述符为复制的副本
+func getListenerFile(ln net.Listener) (*os.File, error) {
+	fileLn, ok := ln.(interface{ File() (*os.File, error) })
+	if !ok {
+		return nil, fmt.Errorf("listener %s does not support file handoff", ln.Addr())
+	}
+	return fileLn.File()
+}
+
+// 恢复监听套接字的非阻塞模式
+// 启动子进程时会将传递的文件设置为阻塞模式，由于文件描述符副本共享同一个打开的文件，当前进程的监听器也会变为阻塞模式，
+// 导致关闭监听器时阻塞。net.FileListener会将复制的文件描述符设置为非阻塞模式，从而恢复共享的打开文件
+func restoreNonblock(files []*os.File) {
+	for _, file := range files {
+		if ln, err := net.FileListener(file); err == nil {
+			ln.Close()
+		}
+	}
+}
+
+// 零停机重启：启动新的可执行文件并传递所有监听器，等待新进程就绪
+// 返回nil时新进程已开始服务，当前进程应优雅关闭；返回错误时新进程已被终止，当前进程继续服务
+func (g *goStar) restart() error {
+	executable, err := os.Executable()
+	if err != nil {
+		return err
+	}
+
+	g.listenersLock.Lock()
+	listeners := append([]*bindListener(nil), g.listeners...)
+	g.listenersLock.Unlock()
+
+	var (
+		files     []*os.File
+		inherited []inheritedListener
+	)
+	defer func() {
+		for _, file := range files {
+			file.Close()
+		}
+	}()
+	for _, ln := range listeners {
+		file, err := getListenerFile(ln.Listener)
+		if err != nil {
+			return err
+		}
+		// 子进程中的文件描述符从3开始
+		inherited = append(inherited, inheritedListener{FD: 3 + len(files), Kind: ln.kind, Address: ln.address})
+		files = append(files, file)
+	}
+	data, err := json.Marshal(inherited)
+	if err != nil {
+		return err
+	}
+
+	readyReader, readyWriter, err := os.Pipe()
+	if err != nil {
+		return err
+	}
+	defer readyReader.Close()
+
+	cmd := exec.Command(executable, os.Args[1:]...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.Env = append(os.Environ(),
+		envInheritedListeners+"="+string(data),
+		envRestartReadyFD+"="+strconv.Itoa(3+len(files)),
+	)
+	cmd.ExtraFiles = append(files, readyWriter)
+
+	err = cmd.Start()
+	readyWriter.Close()
+	restoreNonblock(files)
+	if err != nil {
+		return err
+	}
+	g.logger.I("Started new process %d, waiting for it to be ready", cmd.Process.Pid)
+	// 回收新进程，避免新进程提前退出时成为僵尸进程
+	go cmd.Wait()
+
+	ready := make(chan error, 1)
+	go func() {
+		buf := make([]byte, 1)
+		_, err := readyReader.Read(buf)
+		ready <- err
+	}()
+
+	timeout := g.config.Load().Restart.readyTimeout()
+	select {
+	case err := <-ready:
+		if err != nil {
+			cmd.Process.Kill()
+			return fmt.Errorf("new process exited before ready: %w", err)
+		}
+	case <-time.After(timeout):
+		cmd.Process.Kill()
+		return errors.New("new process not ready after " + timeout.String())
+	}
+	// 套接字文件已由新进程使用，当前进程关闭时不再删除
+	for _, ln := range listeners {
+		if unixLn, ok := ln.Listener.(*net.UnixListener); ok {
+			unixLn.SetUnlinkOnClose(false)
+		}
+	}
+
+	return nil
+}
@@ -1,15 +1,34 @@
 package gostar
 
 import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+
 	"github.com/shi-yunsheng/gostar/router"
 	"github.com/shi-yunsheng/gostar/router/middleware"
 )
 
+// 路由表输出文件，设置后不初始化数据库和Redis，Run时将路由信息以JSON格式写入该文件并返回ErrRoutesDumped，供gostar routes命令使用
+const envRoutesFile = "GOSTAR_ROUTES_FILE"
+
 // 使用路由
 func (g *goStar) UseRouter(routes []router.Route) {
 	g.router.UseRoute(routes)
 }
 
+// 创建路由组，通过链式调用添加路由，如：app.Group("/api/v1", auth).GET("/users/{id:int}", h)
+// 路由组会在启动服务时挂载，挂载后再添加的路由不会生效
+func (g *goStar) Group(path string, middleware ...middleware.Middleware) *router.Group {
+	return g.router.Group(path, middleware...)
+}
+
+// 使用路由注册器注册路由，各个包可以实现router.RouteRegistrar接口注册自己的路由组
+func (g *goStar) Register(registrars ...router.RouteRegistrar) {
+	g.router.Register(registrars...)
+}
+
 // 使用路由中间件
 func (g *goStar) UseMiddleware(middleware ...middleware.Middleware) {
 	g.router.UseMiddleware(middleware...)
@@ -21,3 +40,26 @@ func (g *goStar) UseSecretKey(secretKey map[string]string) {
 		g.router.UseSecretKey(key, value)
 	}
 }
+
+// 获取路由信息
+func (g *goStar) GetRouteInfos() []router.RouteInfo {
+	return g.router.GetRouteInfos()
+}
+
+// 根据路由名称生成URL，params为路径参数，query为查询参数
+func (g *goStar) URL(name string, params map[string]any, query url.Values) (string, error) {
+	return g.router.URL(name, params, query)
+}
+
+// 将路由信息以JSON格式写入GOSTAR_ROUTES_FILE指定的文件
+func (g *goStar) dumpRoutes() error {
+	file := os.Getenv(envRoutesFile)
+	data, err := json.MarshalIndent(g.GetRouteInfos(), "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(file, data, 0644); err != nil {
+		return fmt.Errorf("write routes to %s failed: %w", file, err)
+	}
+	return nil
+}
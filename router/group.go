@@ -0,0 +1,193 @@
+package router
+
+import (
+	"github.com/shi-yunsheng/gostar/router/handler"
+	"github.com/shi-yunsheng/gostar/router/middleware"
+)
+
+// 路由注册器，各个包可以实现该接口，在传入的路由组中注册自己的路由
+type RouteRegistrar interface {
+	RegisterRoutes(group *Group)
+}
+
+// 路由注册函数，实现了RouteRegistrar接口
+type RouteRegistrarFunc func(group *Group)
+
+// 在路由组中注册路由
+func (f RouteRegistrarFunc) RegisterRoutes(group *Group) {
+	f(group)
+}
+
+// 路由组，通过链式调用构建路由，最终编译为与[]Route相同的路由树，
+// 中间件、认证密钥和管理路由设置的继承与嵌套路由相同
+type Group struct {
+	// 路由组对应的路由，子路由和子路由组在编译时加入Children
+	route Route
+	// 子路由和子路由组，按添加顺序编译
+	children []groupChild
+	// 是否已挂载到路由器，只有顶层路由组会被挂载
+	mounted bool
+}
+
+// 路由组的子项，route和group只有一个不为空
+type groupChild struct {
+	route *Route
+	group *Group
+}
+
+// 新建路由组
+func newGroup(path string, middleware ...middleware.Middleware) *Group {
+	return &Group{route: Route{Path: path, Middleware: middleware}}
+}
+
+// 创建路由组，路由组会在启动服务时挂载，挂载后再添加的路由不会生效
+func (r *Router) Group(path string, middleware ...middleware.Middleware) *Group {
+	group := newGroup(path, middleware...)
+	r.groups = append(r.groups, group)
+	return group
+}
+
+// 使用路由注册器注册路由，每个注册器使用一个没有路径前缀的路由组
+func (r *Router) Register(registrars ...RouteRegistrar) {
+	for _, registrar := range registrars {
+		registrar.RegisterRoutes(r.Group(""))
+	}
+}
+
+// 挂载尚未挂载的路由组
+func (r *Router) MountGroups() {
+	routes := make([]Route, 0)
+	for _, group := range r.groups {
+		if group.mounted {
+			continue
+		}
+		group.mounted = true
+		if !group.empty() {
+			routes = append(routes, group.Route())
+		}
+	}
+	if len(routes) > 0 {
+		r.UseRoute(routes)
+	}
+}
+
+// 创建子路由组
+func (g *Group) Group(path string, middleware ...middleware.Middleware) *Group {
+	group := newGroup(path, middleware...)
+	g.children = append(g.children, groupChild{group: group})
+	return group
+}
+
+// 使用路由注册器在路由组中注册路由
+func (g *Group) Register(registrars ...RouteRegistrar) *Group {
+	for _, registrar := range registrars {
+		registrar.RegisterRoutes(g)
+	}
+	return g
+}
+
+// 设置路由组名称，子路由的名称会加上该前缀
+func (g *Group) Name(name string) *Group {
+	g.route.Name = name
+	return g
+}
+
+// 添加路由组中间件
+func (g *Group) Use(middleware ...middleware.Middleware) *Group {
+	g.route.Middleware = append(g.route.Middleware, middleware...)
+	return g
+}
+
+// 设置路由组认证密钥
+func (g *Group) SecretKey(secretKey map[string]string) *Group {
+	if g.route.SecretKey == nil {
+		g.route.SecretKey = make(map[string]string)
+	}
+	for key, value := range secretKey {
+		g.route.SecretKey[key] = value
+	}
+	return g
+}
+
+// 设置路由组为管理路由
+func (g *Group) Admin() *Group {
+	g.route.Admin = true
+	return g
+}
+
+// 添加路由，可以设置Name、Bind、Static、Webapp和Websocket等完整配置
+func (g *Group) Add(route Route) *Group {
+	g.children = append(g.children, groupChild{route: &route})
+	return g
+}
+
+// 添加指定HTTP方法的路由
+func (g *Group) Handle(method Method, path string, handler handler.Handler, middleware ...middleware.Middleware) *Group {
+	return g.Add(Route{Method: method, Path: path, Handler: handler, Middleware: middleware})
+}
+
+// 添加不限制HTTP方法的路由
+func (g *Group) Any(path string, handler handler.Handler, middleware ...middleware.Middleware) *Group {
+	return g.Handle("", path, handler, middleware...)
+}
+
+// 添加GET路由
+func (g *Group) GET(path string, handler handler.Handler, middleware ...middleware.Middleware) *Group {
+	return g.Handle(GET, path, handler, middleware...)
+}
+
+// 添加POST路由
+func (g *Group) POST(path string, handler handler.Handler, middleware ...middleware.Middleware) *Group {
+	return g.Handle(POST, path, handler, middleware...)
+}
+
+// 添加PUT路由
+func (g *Group) PUT(path string, handler handler.Handler, middleware ...middleware.Middleware) *Group {
+	return g.Handle(PUT, path, handler, middleware...)
+}
+
+// 添加PATCH路由
+func (g *Group) PATCH(path string, handler handler.Handler, middleware ...middleware.Middleware) *Group {
+	return g.Handle(PATCH, path, handler, middleware...)
+}
+
+// 添加DELETE路由
+func (g *Group) DELETE(path string, handler handler.Handler, middleware ...middleware.Middleware) *Group {
+	return g.Handle(DELETE, path, handler, middleware...)
+}
+
+// 添加OPTIONS路由
+func (g *Group) OPTIONS(path string, handler handler.Handler, middleware ...middleware.Middleware) *Group {
+	return g.Handle(OPTIONS, path, handler, middleware...)
+}
+
+// 添加HEAD路由
+func (g *Group) HEAD(path string, handler handler.Handler, middleware ...middleware.Middleware) *Group {
+	return g.Handle(HEAD, path, handler, middleware...)
+}
+
+// 将路由组编译为路由
+func (g *Group) Route() Route {
+	route := g.route
+	route.Children = make([]Route, 0, len(g.children))
+	for _, child := range g.children {
+		if child.group != nil {
+			if !child.group.empty() {
+				route.Children = append(route.Children, child.group.Route())
+			}
+		} else {
+			route.Children = append(route.Children, *child.route)
+		}
+	}
+	return route
+}
+
+// 判断路由组是否没有任何路由
+func (g *Group) empty() bool {
+	for _, child := range g.children {
+		if child.route != nil || !child.group.empty() {
+			return false
+		}
+	}
+	return true
+}
@@ -1,157 +1,273 @@
 package router
 
 import (
-	"regexp"
-	"strconv"
+	"net/http"
+	"slices"
 	"strings"
 
-	"github.com/shi-yunsheng/gostar/date"
 	"github.com/shi-yunsheng/gostar/router/handler"
 )
 
-// 递归获取路由的Method（子路由优先，如果为空则使用父路由）
-func (r *Router) getMethod(route *Route) Method {
-	if route.Method != "" {
-		return route.Method
+// 匹配路由，先匹配与请求域名匹配的域名路由，域名路由中没有允许该请求方式的路由时使用不限制域名的路由，
+// 返回路由和路径参数（包含域名参数），路径匹配但请求方式不允许时返回所有路由表中允许的方法
+func (r *Router) match(method, host, path, listener string) (*Route, []handler.Param, []string) {
+	host = normalizeHost(host)
+	allow := make([]string, 0)
+	for _, table := range r.hosts {
+		hostParams, ok := table.matchHost(host)
+		if !ok {
+			continue
+		}
+		route, params, tableAllow := table.match(method, path, listener)
+		if route != nil {
+			return route, append(params, hostParams...), nil
+		}
+		allow = append(allow, tableAllow...)
+	}
+	route, params, tableAllow := r.routeTable.match(method, path, listener)
+	if route != nil {
+		return route, params, nil
+	}
+	return nil, nil, mergeAllow(append(allow, tableAllow...))
+}
+
+// 匹配路由，先按优先级在路由树中匹配，再按顺序使用正则路由匹配，并根据请求方式和监听地址选择路由，
+// 请求方式不允许或参数值无法转换为对应类型（如超出范围的整数）时继续尝试优先级更低的路由，
+// 返回路由和路径参数，没有可用的路由但路径匹配时返回允许的方法
+func (t *routeTable) match(method, path, listener string) (*Route, []handler.Param, []string) {
+	// 如果路径以/结尾，则去掉/
+	path = strings.TrimSuffix(path, "/")
+
+	var (
+		route  *Route
+		params []handler.Param
+		allow  []string
+	)
+	t.tree.walk(path, nil, func(leaves []*leaf, values []string) bool {
+		routes := make([]*Route, len(leaves))
+		for i, l := range leaves {
+			routes[i] = l.route
+		}
+		index, leafAllow := selectRoute(routes, method, listener)
+		if index < 0 {
+			allow = append(allow, leafAllow...)
+			return false
+		}
+		var ok bool
+		if params, ok = buildParams(leaves[index], values); !ok {
+			return false
+		}
+		route = leaves[index].route
+		return true
+	})
+	if route != nil {
+		return route, params, nil
+	}
+
+	tried := make(map[string]bool)
+	for _, rt := range t.regexRoutes {
+		if tried[rt.Path] || !rt.regex.MatchString(path) {
+			continue
+		}
+		tried[rt.Path] = true
+		// 同一路径的其他方法的路由
+		routes := make([]*Route, 0)
+		for _, other := range t.regexRoutes {
+			if other.Path == rt.Path {
+				routes = append(routes, other)
+			}
+		}
+		index, routeAllow := selectRoute(routes, method, listener)
+		if index < 0 {
+			allow = append(allow, routeAllow...)
+			continue
+		}
+		params, ok := parseParam(routes[index], path)
+		if !ok {
+			continue
+		}
+		return routes[index], params, nil
+	}
+	return nil, nil, mergeAllow(allow)
+}
+
+// 合并允许的方法并排序去重，为空时返回nil
+func mergeAllow(allow []string) []string {
+	if len(allow) == 0 {
+		return nil
 	}
+	slices.Sort(allow)
+	return slices.Compact(allow)
+}
 
-	if route.parent != "" && r.routes[route.parent] != nil {
-		return r.getMethod(r.routes[route.parent])
+// 从同一路径的路由中选择允许该请求方式的路由，优先选择明确指定该方法的路由，其次是不限制方法的路由，
+// HEAD请求没有对应路由时使用GET路由。没有可选路由时返回-1和允许的方法，允许的方法为空表示路由不存在
+func selectRoute(routes []*Route, method, listener string) (int, []string) {
+	// 管理路由只在管理监听地址上提供，其他路由只在普通监听地址上提供
+	available := make([]int, 0, len(routes))
+	for i, route := range routes {
+		switch listener {
+		case handler.PublicListener:
+			if route.Admin {
+				continue
+			}
+		case handler.AdminListener:
+			if !route.Admin {
+				continue
+			}
+		}
+		available = append(available, i)
 	}
 
-	return ""
+	find := func(method Method) int {
+		for _, i := range available {
+			if slices.Contains(routes[i].methods, method) {
+				return i
+			}
+		}
+		return -1
+	}
+	if i := find(Method(method)); i >= 0 {
+		return i, nil
+	}
+	for _, i := range available {
+		if len(routes[i].methods) == 0 {
+			return i, nil
+		}
+	}
+	if method == string(HEAD) {
+		if i := find(GET); i >= 0 {
+			return i, nil
+		}
+	}
+
+	allow := make([]string, 0)
+	for _, i := range available {
+		for _, m := range routes[i].methods {
+			allow = append(allow, string(m))
+			if m == GET {
+				allow = append(allow, string(HEAD))
+			}
+		}
+	}
+	if len(allow) == 0 {
+		return -1, nil
+	}
+	allow = append(allow, string(OPTIONS))
+	slices.Sort(allow)
+	return -1, slices.Compact(allow)
 }
 
-// 解析参数
-func (r *Router) parseParam(route *Route, path string) []handler.Param {
+// 根据路由树的捕获值生成路径参数，省略的可选参数使用默认值，参数值无法转换为对应类型时返回false
+func buildParams(l *leaf, values []string) ([]handler.Param, bool) {
+	route := l.route
+	params := make([]handler.Param, len(route.allParams), len(route.allParams)+1)
+	copy(params, route.allParams)
+	for i := range params {
+		params[i].Value = params[i].Default
+	}
+	for i, index := range l.paramIndex {
+		value := values[i]
+		// 通配参数的值为剩余路径，不包含开头的/
+		if params[index].CatchAll {
+			value = strings.TrimPrefix(value, "/")
+		}
+		if !setParamValue(&params[index], value) {
+			return nil, false
+		}
+	}
+	// 如果是static或webapp，将剩余路径视为文件路径
+	if route.Static != nil || route.Webapp != nil {
+		filePath := ""
+		if len(values) > len(l.paramIndex) {
+			filePath = values[len(l.paramIndex)]
+		}
+		params = append(params, handler.Param{Key: filePathParam, Value: filePath})
+	}
+	return params, true
+}
+
+// 设置参数值，值为空时保留默认值，值无法转换为参数类型时返回false
+func setParamValue(param *handler.Param, value string) bool {
+	if value == "" {
+		return true
+	}
+	// 根据类型进行转换
+	convertedValue, err := convertParamValue(value, param.Type)
+	if err != nil {
+		return false
+	}
+	param.Value = convertedValue
+	return true
+}
+
+// 使用正则路由的路径解析参数，参数值无法转换为对应类型时返回false
+func parseParam(route *Route, path string) ([]handler.Param, bool) {
 	// 合并父路由和当前路由的参数
 	params := make([]handler.Param, 0)
-	if route.parent != "" && r.routes[route.parent] != nil && len(r.routes[route.parent].params) > 0 {
-		params = append(params, r.routes[route.parent].params...)
+	if route.parent != nil && len(route.parent.params) > 0 {
+		params = append(params, route.parent.params...)
 	}
 	if len(route.params) > 0 {
 		params = append(params, route.params...)
 	}
+	for i := range params {
+		params[i].Value = params[i].Default
+	}
 	// 正则匹配参数
-	re := regexp.MustCompile(route.Path)
-	allMatches := re.FindAllStringSubmatch(path, -1)
-
+	allMatches := route.regex.FindAllStringSubmatch(path, -1)
 	if len(allMatches) == 0 {
-		return nil
-	}
-
-	matches := allMatches[0][1:]
-	// 预设默认值
-	if len(matches) < len(params) {
-		for i := len(matches); i < len(params); i++ {
-			params[i].Value = params[i].Default
-		}
+		return nil, true
 	}
 	// 设置参数值
-	for i, match := range matches {
+	for i, match := range allMatches[0][1:] {
 		// 如果参数数量小于匹配数量
 		if i >= len(params) {
 			// 如果是static或webapp，将后面的视为文件路径
 			if route.Static != nil || route.Webapp != nil {
 				params = append(params, handler.Param{
-					Key:   "__filepath__",
+					Key:   filePathParam,
 					Value: match,
 				})
 			}
 
 			break
 		}
-
-		reg := regexp.MustCompile(params[i].Pattern)
-		if reg.MatchString(match) {
-			// 根据类型进行转换
-			convertedValue, err := convertParamValue(match, params[i].Type)
-			if err != nil {
-				if !params[i].Optional {
-					panic("invalid path parameter value, must be " + params[i].Type + ". Got: " + match + ". Error: " + err.Error())
-				}
-			} else {
-				params[i].Value = convertedValue
-			}
-		} else if !params[i].Optional {
-			panic("invalid path parameter value, must be " + params[i].Type + ". Got: " + match)
+		if !setParamValue(&params[i], match) {
+			return nil, false
 		}
 	}
 
-	return params
+	return params, true
 }
 
 // 转换参数值
 func convertParamValue(value string, paramType string) (any, error) {
-	switch paramType {
-	case "int":
-		val, err := strconv.ParseInt(value, 10, 64)
-		if err != nil {
-			return nil, err
-		}
-		return val, nil
-	case "float":
-		val, err := strconv.ParseFloat(value, 64)
-		if err != nil {
-			return nil, err
-		}
-		return val, nil
-	case "bool":
-		val, err := strconv.ParseBool(value)
-		if err != nil {
-			return nil, err
-		}
-		return val, nil
-	case "date":
-		val, err := date.ParseTimeString(value)
-		if err != nil {
-			val, err = date.ParseTimestamp(value)
-			if err != nil {
-				return nil, err
-			}
-		}
-		return val, nil
-	case "str", "":
-		// 默认类型为字符串
-		return value, nil
-	default:
+	typ, ok := lookupParamType(paramType)
+	if !ok {
 		// 未知类型，返回原值
 		return value, nil
 	}
+	return typ.convert(value)
 }
 
 // 根处理器，所有请求都会经过这里
 func (r *Router) serveHTTP(w *handler.Response, req handler.Request) any {
-	path := req.URL.Path
-
-	route, ok := r.routes[path]
-	// 如果获取不到，进行正则匹配
-	if !ok {
-		// 如果路径以/结尾，则去掉/
-		if after, ok := strings.CutSuffix(path, "/"); ok {
-			path = after
-		}
-
-		for _, rt := range r.sortedRoutes {
-			re := regexp.MustCompile(r.routes[rt].Path)
-			if re.MatchString(path) {
-				route = r.routes[rt]
-				break
-			}
-		}
-	}
-
+	route, params, allow := r.match(req.Method, req.Host, req.URL.Path, req.GetListener())
 	if route == nil {
-		handler.NotFound(w, req)
+		switch {
+		case len(allow) == 0:
+			handler.NotFound(w, req)
+		case req.Method == string(OPTIONS):
+			// 自动响应OPTIONS请求
+			w.SetHeader("Allow", strings.Join(allow, ", "))
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			handler.MethodNotAllowed(w, req, allow...)
+		}
 		return nil
 	}
 
-	// 验证请求方式（递归检查父路由）
-	method := r.getMethod(route)
-	if method != "" && string(method) != req.Method {
-		handler.MethodNotAllowed(w, req)
-		return nil
-	}
 	// 验证SecretKey
 	if r.secretKey != nil {
 		for key, value := range r.secretKey {
@@ -170,13 +286,17 @@ func (r *Router) serveHTTP(w *handler.Response, req handler.Request) any {
 		}
 	}
 
-	req.SetParams(r.parseParam(route, path))
+	req.SetParams(params)
 	if route.Bind != nil {
 		model, err := route.Validate(&req)
 		if err != nil {
 			if model != nil {
 				return model
 			}
+			if handler.IsRequestEntityTooLarge(err) {
+				handler.RequestEntityTooLarge(w, req)
+				return nil
+			}
 
 			handler.BadRequest(w, req, err)
 			return nil
@@ -0,0 +1,114 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/shi-yunsheng/gostar/i18n"
+	"github.com/shi-yunsheng/gostar/logger"
+)
+
+// 框架使用的上下文键
+const (
+	appContextKey            = "__gostar_app__"
+	loggerContextKey         = "__gostar_logger__"
+	debugContextKey          = "__gostar_debug__"
+	websocketGroupContextKey = "__gostar_websocket_group__"
+	listenerContextKey       = "__gostar_listener__"
+	langContextKey           = "__gostar_lang__"
+	fallbackLangContextKey   = "__gostar_fallback_lang__"
+)
+
+// 监听地址类型
+const (
+	// 普通监听地址，只提供非管理路由
+	PublicListener = "public"
+	// 管理监听地址，只提供管理路由
+	AdminListener = "admin"
+)
+
+// 标记请求来自的监听地址类型，未标记的请求可以访问所有路由
+func WithListener(r *http.Request, listener string) *http.Request {
+	return r.WithContext(context.WithValue(r.Context(), contextKey(listenerContextKey), listener))
+}
+
+// 获取请求来自的监听地址类型，未标记时返回空字符串
+func (r *Request) GetListener() string {
+	listener, _ := r.GetContext(listenerContextKey).(string)
+	return listener
+}
+
+// 设置处理当前请求的应用实例
+func (r *Request) SetApp(app any) {
+	r.AddContext(appContextKey, app)
+}
+
+// 获取处理当前请求的应用实例，未设置时返回nil
+func (r *Request) GetApp() any {
+	return r.GetContext(appContextKey)
+}
+
+// 设置当前请求使用的日志器
+func (r *Request) SetLogger(l *logger.Logger) {
+	r.AddContext(loggerContextKey, l)
+}
+
+// 获取当前请求使用的日志器，未设置时返回默认日志器
+func (r *Request) GetLogger() *logger.Logger {
+	if l, ok := r.GetContext(loggerContextKey).(*logger.Logger); ok && l != nil {
+		return l
+	}
+	return logger.Default()
+}
+
+// 设置当前请求的调试模式
+func (r *Request) SetDebug(debug bool) {
+	r.AddContext(debugContextKey, debug)
+}
+
+// 当前请求是否处于调试模式，未设置时使用全局调试模式
+func (r *Request) IsDebug() bool {
+	if d, ok := r.GetContext(debugContextKey).(bool); ok {
+		return d
+	}
+	return debug
+}
+
+// 设置当前请求的websocket连接组
+func (r *Request) SetWebsocketGroup(group *WebsocketGroup) {
+	r.AddContext(websocketGroupContextKey, group)
+}
+
+// 获取当前请求的websocket连接组，未设置时返回默认连接组
+func (r *Request) GetWebsocketGroup() *WebsocketGroup {
+	if group, ok := r.GetContext(websocketGroupContextKey).(*WebsocketGroup); ok && group != nil {
+		return group
+	}
+	return defaultWebsocketGroup
+}
+
+// 设置当前请求使用的语言，设置后不再根据Accept-Language选择语言
+func (r *Request) SetLang(lang string) {
+	r.AddContext(langContextKey, lang)
+}
+
+// 设置当前请求的备用语言，Accept-Language中没有支持的语言时使用
+func (r *Request) SetFallbackLang(lang string) {
+	r.AddContext(fallbackLangContextKey, lang)
+}
+
+// 获取当前请求使用的语言，未通过SetLang设置时根据Accept-Language选择，没有匹配时使用备用语言或默认语言
+func (r *Request) GetLang() string {
+	if lang, ok := r.GetContext(langContextKey).(string); ok && lang != "" {
+		if resolved := i18n.Resolve(lang); resolved != "" {
+			return resolved
+		}
+	}
+	fallback, _ := r.GetContext(fallbackLangContextKey).(string)
+	return i18n.Match(r.GetHeader("Accept-Language"), fallback)
+}
+
+// 翻译消息，使用当前请求的语言
+func (r *Request) T(key string, args ...any) string {
+	return i18n.T(r.GetLang(), key, args...)
+}
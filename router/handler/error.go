@@ -2,11 +2,14 @@ package handler
 
 import (
 	"bytes"
-	"fmt"
+	"errors"
 	"html/template"
+	"maps"
 	"net/http"
+	"slices"
 	"strings"
 
+	"github.com/shi-yunsheng/gostar/i18n"
 	"github.com/shi-yunsheng/gostar/utils"
 )
 
@@ -18,6 +21,7 @@ func EnableDebug() {
 }
 
 type ErrorHtml struct {
+	Lang        string `json:"lang"`
 	Title       string `json:"title"`
 	Code        string `json:"code"`
 	Description string `json:"description"`
@@ -26,7 +30,7 @@ type ErrorHtml struct {
 }
 
 var errorTemplate = `<!DOCTYPE html>
-<html lang="">
+<html lang="{{.Lang}}">
 <head>
     <meta charset="UTF-8">
     <meta name="viewport" content="width=device-width, initial-scale=1.0">
@@ -162,7 +166,7 @@ func NotFound(w *Response, r Request) {
 
 	result := map[string]any{
 		"code":    404,
-		"message": "Not Found",
+		"message": r.T(i18n.NotFound),
 	}
 
 	if r.IsWebsocket() {
@@ -173,10 +177,11 @@ func NotFound(w *Response, r Request) {
 
 	if r.Method == "GET" && strings.Contains(r.GetHeader("Accept"), "text/html") {
 		errorHtml := ErrorHtml{
-			Title:       "404 Not Found",
+			Lang:        r.GetLang(),
+			Title:       "404 " + r.T(i18n.NotFound),
 			Code:        "404",
-			Description: "Not Found",
-			Message:     "Sorry, the page you visited does not exist.",
+			Description: r.T(i18n.NotFound),
+			Message:     r.T(i18n.NotFoundMessage),
 		}
 
 		w.Html(errorPage(errorHtml))
@@ -185,13 +190,16 @@ func NotFound(w *Response, r Request) {
 	}
 }
 
-// 405 请求方法不允许
-func MethodNotAllowed(w *Response, r Request) {
+// 405 请求方法不允许，allow为允许的请求方法，会写入Allow响应头
+func MethodNotAllowed(w *Response, r Request, allow ...string) {
+	if len(allow) > 0 {
+		w.SetHeader("Allow", strings.Join(allow, ", "))
+	}
 	w.WriteHeader(http.StatusMethodNotAllowed)
 
 	result := map[string]any{
 		"code":    405,
-		"message": "Method Not Allowed",
+		"message": r.T(i18n.MethodNotAllowed),
 	}
 
 	if r.IsWebsocket() {
@@ -202,10 +210,11 @@ func MethodNotAllowed(w *Response, r Request) {
 
 	if r.Method == "GET" && strings.Contains(r.GetHeader("Accept"), "text/html") {
 		errorHtml := ErrorHtml{
-			Title:       "405 Method Not Allowed",
+			Lang:        r.GetLang(),
+			Title:       "405 " + r.T(i18n.MethodNotAllowed),
 			Code:        "405",
-			Description: "Method Not Allowed",
-			Message:     "Sorry, the method you used is not allowed.",
+			Description: r.T(i18n.MethodNotAllowed),
+			Message:     r.T(i18n.MethodNotAllowedMessage),
 		}
 		w.Html(errorPage(errorHtml))
 	} else {
@@ -219,7 +228,7 @@ func Unauthorized(w *Response, r Request) {
 
 	result := map[string]any{
 		"code":    401,
-		"message": "Unauthorized",
+		"message": r.T(i18n.Unauthorized),
 	}
 
 	if r.IsWebsocket() {
@@ -230,10 +239,11 @@ func Unauthorized(w *Response, r Request) {
 
 	if r.Method == "GET" && strings.Contains(r.GetHeader("Accept"), "text/html") {
 		errorHtml := ErrorHtml{
-			Title:       "401 Unauthorized",
+			Lang:        r.GetLang(),
+			Title:       "401 " + r.T(i18n.Unauthorized),
 			Code:        "401",
-			Description: "Unauthorized",
-			Message:     "Sorry, you are not authorized to access this page.",
+			Description: r.T(i18n.Unauthorized),
+			Message:     r.T(i18n.UnauthorizedMessage),
 		}
 		w.Html(errorPage(errorHtml))
 	} else {
@@ -247,7 +257,7 @@ func Forbidden(w *Response, r Request) {
 
 	result := map[string]any{
 		"code":    403,
-		"message": "Forbidden",
+		"message": r.T(i18n.Forbidden),
 	}
 
 	if r.IsWebsocket() {
@@ -258,10 +268,11 @@ func Forbidden(w *Response, r Request) {
 
 	if r.Method == "GET" && strings.Contains(r.GetHeader("Accept"), "text/html") {
 		errorHtml := ErrorHtml{
-			Title:       "403 Forbidden",
+			Lang:        r.GetLang(),
+			Title:       "403 " + r.T(i18n.Forbidden),
 			Code:        "403",
-			Description: "Forbidden",
-			Message:     "Sorry, you are not allowed to access this page.",
+			Description: r.T(i18n.Forbidden),
+			Message:     r.T(i18n.ForbiddenMessage),
 		}
 		w.Html(errorPage(errorHtml))
 	} else {
@@ -275,7 +286,7 @@ func InternalServerError(w *Response, r Request, err ...error) {
 
 	result := map[string]any{
 		"code":    500,
-		"message": "Internal Server Error",
+		"message": r.T(i18n.InternalServerError),
 	}
 
 	if r.IsWebsocket() {
@@ -285,25 +296,26 @@ func InternalServerError(w *Response, r Request, err ...error) {
 	}
 
 	if r.Method == "GET" && strings.Contains(r.GetHeader("Accept"), "text/html") {
-		message := "Sorry, the server is busy, please try again later."
+		message := r.T(i18n.InternalServerErrorMessage)
 		if len(err) > 0 {
-			message += fmt.Sprintf(" ERROR: %s", err[0].Error())
+			message += r.T(i18n.ErrorDetail, err[0].Error())
 		}
 
 		errorHtml := ErrorHtml{
-			Title:       "500 Internal Server Error",
+			Lang:        r.GetLang(),
+			Title:       "500 " + r.T(i18n.InternalServerError),
 			Code:        "500",
-			Description: "Internal Server Error",
+			Description: r.T(i18n.InternalServerError),
 			Message:     message,
 		}
 
-		if debug {
+		if r.IsDebug() {
 			errorHtml.Stack = utils.GetStackTrace()
 		}
 
 		w.Html(errorPage(errorHtml))
 	} else {
-		if debug {
+		if r.IsDebug() {
 			result["stack"] = utils.GetStackTrace()
 		}
 
@@ -317,7 +329,15 @@ func BadRequest(w *Response, r Request, err ...error) {
 
 	result := map[string]any{
 		"code":    400,
-		"message": "Bad Request",
+		"message": r.T(i18n.BadRequest),
+	}
+	// 校验错误按当前请求的语言翻译后返回
+	var fieldErrors map[string]string
+	if len(err) > 0 {
+		if translated, ok := i18n.TranslateValidationErrors(r.GetLang(), err[0]); ok {
+			fieldErrors = translated
+			result["errors"] = fieldErrors
+		}
 	}
 
 	if r.IsWebsocket() {
@@ -327,15 +347,18 @@ func BadRequest(w *Response, r Request, err ...error) {
 	}
 
 	if r.Method == "GET" && strings.Contains(r.GetHeader("Accept"), "text/html") {
-		message := "Sorry, the request is invalid."
-		if len(err) > 0 {
-			message += fmt.Sprintf(" ERROR: %s", err[0].Error())
+		message := r.T(i18n.BadRequestMessage)
+		if len(fieldErrors) > 0 {
+			message += r.T(i18n.ErrorDetail, joinFieldErrors(fieldErrors))
+		} else if len(err) > 0 {
+			message += r.T(i18n.ErrorDetail, err[0].Error())
 		}
 
 		errorHtml := ErrorHtml{
-			Title:       "400 Bad Request",
+			Lang:        r.GetLang(),
+			Title:       "400 " + r.T(i18n.BadRequest),
 			Code:        "400",
-			Description: "Bad Request",
+			Description: r.T(i18n.BadRequest),
 			Message:     message,
 		}
 
@@ -344,3 +367,47 @@ func BadRequest(w *Response, r Request, err ...error) {
 		w.Json(result)
 	}
 }
+
+// 413 请求体过大
+func RequestEntityTooLarge(w *Response, r Request) {
+	w.WriteHeader(http.StatusRequestEntityTooLarge)
+
+	result := map[string]any{
+		"code":    413,
+		"message": r.T(i18n.RequestEntityTooLarge),
+	}
+
+	if r.IsWebsocket() {
+		conn := w.GetWebsocketConn()
+		conn.SendJson(result)
+		return
+	}
+
+	if r.Method == "GET" && strings.Contains(r.GetHeader("Accept"), "text/html") {
+		errorHtml := ErrorHtml{
+			Lang:        r.GetLang(),
+			Title:       "413 " + r.T(i18n.RequestEntityTooLarge),
+			Code:        "413",
+			Description: r.T(i18n.RequestEntityTooLarge),
+			Message:     r.T(i18n.RequestEntityTooLargeMessage),
+		}
+		w.Html(errorPage(errorHtml))
+	} else {
+		w.Json(result)
+	}
+}
+
+// 按字段名排序后拼接字段错误
+func joinFieldErrors(fieldErrors map[string]string) string {
+	messages := make([]string, 0, len(fieldErrors))
+	for _, field := range slices.Sorted(maps.Keys(fieldErrors)) {
+		messages = append(messages, fieldErrors[field])
+	}
+	return strings.Join(messages, "; ")
+}
+
+// 判断错误是否由请求体超出大小限制引起
+func IsRequestEntityTooLarge(err error) bool {
+	var maxBytesErr *http.MaxBytesError
+	return errors.As(err, &maxBytesErr)
+}
@@ -14,4 +14,6 @@ type Param struct {
 	Pattern string
 	// 参数可选
 	Optional bool
+	// 是否为通配参数，匹配剩余的全部路径（包含/）
+	CatchAll bool
 }
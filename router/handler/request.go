@@ -3,6 +3,7 @@ package handler
 import (
 	"bytes"
 	"context"
+	"crypto/x509"
 	"encoding/json"
 	"errors"
 	"io"
@@ -162,25 +163,32 @@ func (r *Request) GetClientIP() []string {
 	return utils.RemoveDuplicates(ips)
 }
 
-// 根据键获取上传的文件，支持单文件和批量文件上传
+// 根据键获取上传的文件，支持单文件和批量文件上传，解析表单失败时返回nil，
+// 请求体超出大小限制且处理器未写入响应时框架返回413，需要处理错误时使用GetFileE
 func (r *Request) GetFile(key string, allowType []string) []*multipart.FileHeader {
+	files, _ := r.GetFileE(key, allowType)
+	return files
+}
+
+// 根据键获取上传的文件，解析表单失败时返回错误，请求体超出大小限制时可使用IsRequestEntityTooLarge判断
+func (r *Request) GetFileE(key string, allowType []string) ([]*multipart.FileHeader, error) {
 	// 检查请求是否为多部分表单
 	if r.MultipartForm == nil {
 		// 检查 Content-Type 是否为 multipart/form-data
 		contentType := r.Header.Get("Content-Type")
 		if !strings.HasPrefix(contentType, "multipart/form-data") {
-			return nil
+			return nil, nil
 		}
 		// 解析 multipart form，最大内存 32MB
 		err := r.ParseMultipartForm(32 << 20)
 		if err != nil {
-			return nil
+			return nil, err
 		}
 	}
 	// 从表单中根据键获取文件
 	files := r.MultipartForm.File[key]
 	if len(files) == 0 {
-		return nil
+		return nil, nil
 	}
 
 	var validFiles []*multipart.FileHeader
@@ -211,7 +219,7 @@ func (r *Request) GetFile(key string, allowType []string) []*multipart.FileHeade
 		validFiles = append(validFiles, file)
 	}
 
-	return validFiles
+	return validFiles, nil
 }
 
 // 获取请求头
@@ -261,6 +269,14 @@ func (r *Request) GetContext(key string) any {
 	return ctx.Value(contextKey(key))
 }
 
+// 获取已校验的客户端证书，仅在开启双向认证（mTLS）且客户端提供了证书时有效，否则返回nil
+func (r *Request) GetClientCertificate() *x509.Certificate {
+	if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 || len(r.TLS.VerifiedChains[0]) == 0 {
+		return nil
+	}
+	return r.TLS.VerifiedChains[0][0]
+}
+
 // 判断是否是WebSocket连接
 func (r *Request) IsWebsocket() bool {
 	return r.Method == "GET" &&
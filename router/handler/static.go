@@ -63,17 +63,22 @@ func ThrottledDownloadServer(rootDir http.FileSystem, bytesPerSecond int64) Hand
 	}
 }
 
-var (
-	// 限速下载服务器
-	throttledDownloadServer Handler
-	// 文件键名
-	fileKey = "file"
-	// 上传互斥锁
-	uploadMutex sync.Mutex
-)
+// 上传互斥锁
+var uploadMutex sync.Mutex
 
 // 静态文件处理器
 func StaticServer(handler Handler, staticConfig *Static) Handler {
+	var (
+		// 限速下载服务器
+		throttledDownloadServer Handler
+		throttledDownloadOnce   sync.Once
+		// 文件键名
+		fileKey = "file"
+	)
+	if staticConfig.Upload != nil && staticConfig.Upload.FileKey != "" {
+		fileKey = staticConfig.Upload.FileKey
+	}
+
 	return func(w *Response, r Request) any {
 		if staticConfig.Path == "" || !utils.IsDir(staticConfig.Path) {
 			InternalServerError(w, r, fmt.Errorf("directory does not exist or is not configured"))
@@ -145,9 +150,9 @@ func StaticServer(handler Handler, staticConfig *Static) Handler {
 						return nil
 					}
 					if speedLimit > 0 {
-						if throttledDownloadServer == nil {
+						throttledDownloadOnce.Do(func() {
 							throttledDownloadServer = ThrottledDownloadServer(http.Dir(staticConfig.Path), speedLimit)
-						}
+						})
 						throttledDownloadServer(w, r)
 						return nil
 					}
@@ -158,12 +163,12 @@ func StaticServer(handler Handler, staticConfig *Static) Handler {
 		}
 		// 如果配置了上传，并且有携带文件，则上传文件
 		if staticConfig.Upload != nil {
-			if staticConfig.Upload.FileKey != "" {
-				fileKey = staticConfig.Upload.FileKey
+			files, err := r.GetFileE(fileKey, staticConfig.AllowType)
+			if IsRequestEntityTooLarge(err) {
+				RequestEntityTooLarge(w, r)
+				return nil
 			}
 
-			files := r.GetFile(fileKey, staticConfig.AllowType)
-
 			if len(files) > 0 {
 				filepaths := make([]string, len(files))
 
@@ -19,17 +19,15 @@ type Webapp struct {
 	DisableSPA bool
 }
 
-var (
-	webappIndex string = "index.html"
-	webappPath  string = "./web"
-	assetsPath  string = ""
-	hasCache    bool   = false
-)
-
 // webapp处理器
+// 每个处理器使用独立的配置，同一进程中的多个应用实例互不影响
 func WebApp(handler Handler, webconfig *Webapp) Handler {
+	webappIndex := "index.html"
+	webappPath := "./web"
+	assetsPath := ""
+	disableSPA := false
 	// 如果webconfig不为nil，则设置index和path
-	if webconfig != nil && !hasCache {
+	if webconfig != nil {
 		if webconfig.Index != "" {
 			webappIndex = webconfig.Index
 		}
@@ -39,7 +37,10 @@ func WebApp(handler Handler, webconfig *Webapp) Handler {
 		if webconfig.AssetsPath != "" {
 			assetsPath = webconfig.AssetsPath
 		}
-		hasCache = true
+		disableSPA = webconfig.DisableSPA
+	}
+	if !strings.HasPrefix(webappIndex, "/") {
+		webappIndex = "/" + webappIndex
 	}
 
 	return func(w *Response, r Request) any {
@@ -63,17 +64,13 @@ func WebApp(handler Handler, webconfig *Webapp) Handler {
 				return nil
 			} else {
 				// 如果SPA被禁用，则返回404
-				if webconfig.DisableSPA {
+				if disableSPA {
 					NotFound(w, r)
 					return nil
 				}
 			}
 		}
 
-		if !strings.HasPrefix(webappIndex, "/") {
-			webappIndex = "/" + webappIndex
-		}
-
 		http.ServeFile(w, r.Request, webappPath+webappIndex)
 		return nil
 	}
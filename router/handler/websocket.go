@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"io"
@@ -8,6 +9,7 @@ import (
 	"net/http"
 	"os"
 	"sync"
+	"time"
 
 	"github.com/gorilla/websocket"
 )
@@ -17,13 +19,110 @@ type WebsocketConn struct {
 	ws        *websocket.Conn
 	mutex     sync.Mutex
 	readMutex sync.Mutex
+	group     *WebsocketGroup
 }
 
-// 新建websocket连接
+// websocket连接组，记录活跃的websocket连接，用于优雅关闭时等待连接结束
+type WebsocketGroup struct {
+	conns     map[*WebsocketConn]struct{}
+	connsLock sync.Mutex
+}
+
+// 默认websocket连接组
+var defaultWebsocketGroup = NewWebsocketGroup()
+
+// 新建websocket连接组
+func NewWebsocketGroup() *WebsocketGroup {
+	return &WebsocketGroup{
+		conns: make(map[*WebsocketConn]struct{}),
+	}
+}
+
+// 获取默认websocket连接组
+func DefaultWebsocketGroup() *WebsocketGroup {
+	return defaultWebsocketGroup
+}
+
+// 新建websocket连接，连接记录在默认连接组中
 func NewWebsocketConn(ws *websocket.Conn) *WebsocketConn {
-	return &WebsocketConn{
+	return defaultWebsocketGroup.NewConn(ws)
+}
+
+// 新建websocket连接，连接记录在当前连接组中
+func (g *WebsocketGroup) NewConn(ws *websocket.Conn) *WebsocketConn {
+	conn := &WebsocketConn{
 		ws:    ws,
 		mutex: sync.Mutex{},
+		group: g,
+	}
+
+	if ws != nil {
+		g.connsLock.Lock()
+		g.conns[conn] = struct{}{}
+		g.connsLock.Unlock()
+	}
+
+	return conn
+}
+
+// 获取默认连接组中活跃的websocket连接数量
+func ActiveWebsocketCount() int {
+	return defaultWebsocketGroup.Count()
+}
+
+// 关闭默认连接组中的所有websocket连接
+func ShutdownWebsocket(ctx context.Context) error {
+	return defaultWebsocketGroup.Shutdown(ctx)
+}
+
+// 获取活跃的websocket连接数量
+func (g *WebsocketGroup) Count() int {
+	g.connsLock.Lock()
+	defer g.connsLock.Unlock()
+
+	return len(g.conns)
+}
+
+// 移除连接
+func (g *WebsocketGroup) remove(conn *WebsocketConn) {
+	g.connsLock.Lock()
+	delete(g.conns, conn)
+	g.connsLock.Unlock()
+}
+
+// 关闭所有websocket连接
+// 先向客户端发送关闭帧，然后等待连接被关闭，如果ctx超时则强制关闭剩余连接
+func (g *WebsocketGroup) Shutdown(ctx context.Context) error {
+	g.connsLock.Lock()
+	for conn := range g.conns {
+		message := websocket.FormatCloseMessage(websocket.CloseGoingAway, "server shutting down")
+		_ = conn.ws.WriteControl(websocket.CloseMessage, message, time.Now().Add(time.Second))
+	}
+	g.connsLock.Unlock()
+
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+
+	for {
+		if g.Count() == 0 {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			g.connsLock.Lock()
+			conns := make([]*WebsocketConn, 0, len(g.conns))
+			for conn := range g.conns {
+				conns = append(conns, conn)
+			}
+			g.connsLock.Unlock()
+			// 强制关闭剩余连接
+			for _, conn := range conns {
+				conn.Close()
+			}
+			return ctx.Err()
+		case <-ticker.C:
+		}
 	}
 }
 
@@ -52,6 +151,10 @@ func (w *WebsocketConn) Close() {
 		return
 	}
 
+	if w.group != nil {
+		w.group.remove(w)
+	}
+
 	w.ws.Close()
 }
 
@@ -196,7 +299,10 @@ func ToWebsocketHandler(handler Handler, websocketUpgrade *websocket.Upgrader) H
 			if err != nil {
 				return nil
 			}
-			w.ws = NewWebsocketConn(ws)
+			conn := r.GetWebsocketGroup().NewConn(ws)
+			w.ws = conn
+			// 处理器返回后关闭连接并从连接组中移除，避免未调用Close的连接一直留在连接组中
+			defer conn.Close()
 		}
 
 		return handler(w, r)
@@ -0,0 +1,108 @@
+package router
+
+import (
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/shi-yunsheng/gostar/router/handler"
+)
+
+// 路由匹配表，包含路由树和正则路由
+type routeTable struct {
+	// 路由树
+	tree *node
+	// 包含正则表达式的路由，按路径长度排序，路由树中没有匹配时使用
+	regexRoutes []*Route
+}
+
+// 新建路由匹配表
+func newRouteTable() *routeTable {
+	return &routeTable{tree: newNode()}
+}
+
+// 按路径长度排序正则路由（最长的在前）
+func (t *routeTable) sortRoutes() {
+	sort.SliceStable(t.regexRoutes, func(i, j int) bool {
+		return len(t.regexRoutes[i].Path) > len(t.regexRoutes[j].Path)
+	})
+}
+
+// 域名路由表，只匹配与域名模板匹配的请求
+type hostTable struct {
+	*routeTable
+	// 域名模板，如：{tenant}.example.com
+	host string
+	// 编译后的域名正则
+	regex *regexp.Regexp
+	// 域名参数
+	params []handler.Param
+}
+
+// 获取域名模板对应的路由表，不存在时创建
+func (r *Router) hostTable(host string) *hostTable {
+	for _, table := range r.hosts {
+		if table.host == host {
+			return table
+		}
+	}
+	table := newHostTable(host)
+	r.hosts = append(r.hosts, table)
+	return table
+}
+
+// 新建域名路由表，域名模板中的参数格式与路径参数相同，字符串参数只匹配域名中的一段，如：{tenant}.example.com
+func newHostTable(host string) *hostTable {
+	table := &hostTable{routeTable: newRouteTable(), host: host}
+	var pattern strings.Builder
+	last := 0
+	for _, match := range findParams(host) {
+		pattern.WriteString(regexp.QuoteMeta(strings.ToLower(host[last:match[0]])))
+		param := newParam(host[match[0]+1:match[1]-1], host)
+		if param.CatchAll {
+			panic("catch-all parameter is not supported in host. Got: " + host)
+		}
+		if slices.ContainsFunc(table.params, func(p handler.Param) bool { return p.Key == param.Key }) {
+			panic("duplicate host parameter name: " + param.Key + ". Got: " + host)
+		}
+		if param.Type == "str" {
+			param.Pattern = `([^.]+)`
+		}
+		pattern.WriteString(param.Pattern)
+		if param.Optional {
+			pattern.WriteString("?")
+		}
+		table.params = append(table.params, param)
+		last = match[1]
+	}
+	pattern.WriteString(regexp.QuoteMeta(strings.ToLower(host[last:])))
+	table.regex = regexp.MustCompile("^" + pattern.String() + "$")
+	return table
+}
+
+// 匹配请求域名，返回域名参数
+func (t *hostTable) matchHost(host string) ([]handler.Param, bool) {
+	matches := t.regex.FindStringSubmatch(host)
+	if matches == nil {
+		return nil, false
+	}
+	params := make([]handler.Param, len(t.params))
+	copy(params, t.params)
+	for i := range params {
+		params[i].Value = params[i].Default
+		// 参数值无法转换为对应类型时视为域名不匹配
+		if !setParamValue(&params[i], matches[i+1]) {
+			return nil, false
+		}
+	}
+	return params, true
+}
+
+// 去掉请求域名中的端口并转为小写
+func normalizeHost(host string) string {
+	if i := strings.LastIndexByte(host, ':'); i > strings.LastIndexByte(host, ']') {
+		host = host[:i]
+	}
+	return strings.ToLower(host)
+}
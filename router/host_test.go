@@ -0,0 +1,50 @@
+package router
+
+import (
+	"testing"
+)
+
+func TestHostMatch(t *testing.T) {
+	r := newTestRouter(
+		Route{Path: "/users", Host: "api.example.com", Handler: noopHandler, Method: POST},
+		Route{Path: "/users/{id:int}", Host: "{tenant}.example.com", Handler: noopHandler},
+		Route{Path: "/status", Host: "{shard:int}.db.example.com", Handler: noopHandler},
+		Route{Path: "/users", Handler: noopHandler, Method: GET},
+		Route{Path: "/about", Handler: noopHandler},
+	)
+	runMatchCases(t, r, []matchCase{
+		{name: "static host", method: "POST", host: "api.example.com", path: "/users", want: "/users"},
+		{name: "host port and case", method: "POST", host: "API.Example.com:8080", path: "/users", want: "/users"},
+		{name: "host param", host: "acme.example.com", path: "/users/7", want: "/users/{id:int}", params: map[string]any{"tenant": "acme", "id": int64(7)}},
+		{name: "host param one label", host: "a.b.example.com", path: "/users/7"},
+		{name: "typed host param", host: "3.db.example.com", path: "/status", want: "/status", params: map[string]any{"shard": int64(3)}},
+		{name: "typed host param miss", host: "x.db.example.com", path: "/status"},
+		{name: "typed host param overflow", host: "99999999999999999999.db.example.com", path: "/status"},
+		{name: "fallback path", host: "api.example.com", path: "/about", want: "/about"},
+		{name: "fallback method", method: "GET", host: "api.example.com", path: "/users", want: "/users"},
+		{name: "merged allow", method: "DELETE", host: "api.example.com", path: "/users", allow: []string{"GET", "HEAD", "OPTIONS", "POST"}},
+		{name: "other host", method: "POST", host: "www.example.org", path: "/users", allow: []string{"GET", "HEAD", "OPTIONS"}},
+	})
+}
+
+func TestHostParamConflict(t *testing.T) {
+	cases := []struct {
+		name   string
+		routes []Route
+	}{
+		{name: "host and path", routes: []Route{{Path: "/users/{tenant}", Host: "{tenant}.example.com", Handler: noopHandler}}},
+		{name: "inherited host", routes: []Route{{Path: "/{tenant}", Host: "{tenant}.example.com", Children: []Route{{Path: "/users", Handler: noopHandler}}}}},
+		{name: "duplicate host params", routes: []Route{{Path: "/users", Host: "{id}.{id}.example.com", Handler: noopHandler}}},
+		{name: "catch-all host param", routes: []Route{{Path: "/users", Host: "{rest*}.example.com", Handler: noopHandler}}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Fatal("expected panic")
+				}
+			}()
+			newTestRouter(tc.routes...)
+		})
+	}
+}
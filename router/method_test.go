@@ -0,0 +1,99 @@
+package router
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/shi-yunsheng/gostar/router/handler"
+)
+
+func TestMethodMatch(t *testing.T) {
+	r := newTestRouter(
+		Route{Path: "/users", Method: GET, Handler: noopHandler},
+		Route{Path: "/users", Method: POST, Handler: noopHandler},
+		Route{Path: "/users/{id:int}", Method: Methods(GET, PUT), Handler: noopHandler},
+		Route{Path: "/ping", Handler: noopHandler},
+		Route{Path: "/upload", Method: POST, Handler: noopHandler},
+		Route{Path: "/admin/stats", Method: GET, Handler: noopHandler, Admin: true},
+	)
+	runMatchCases(t, r, []matchCase{
+		{name: "get", path: "/users", want: "/users"},
+		{name: "post", method: "POST", path: "/users", want: "/users"},
+		{name: "head uses get", method: "HEAD", path: "/users", want: "/users"},
+		{name: "method list", method: "PUT", path: "/users/1", want: "/users/{id:int}"},
+		{name: "any method", method: "DELETE", path: "/ping", want: "/ping"},
+		{name: "not allowed", method: "DELETE", path: "/users", allow: []string{"GET", "HEAD", "OPTIONS", "POST"}},
+		{name: "no head without get", method: "HEAD", path: "/upload", allow: []string{"OPTIONS", "POST"}},
+		{name: "options", method: "OPTIONS", path: "/users/1", allow: []string{"GET", "HEAD", "OPTIONS", "PUT"}},
+	})
+
+	route, _, _ := r.match(string(GET), "", "/admin/stats", handler.PublicListener)
+	if route != nil {
+		t.Fatal("admin route matched on public listener")
+	}
+	route, _, _ = r.match(string(GET), "", "/admin/stats", handler.AdminListener)
+	if route == nil {
+		t.Fatal("admin route not matched on admin listener")
+	}
+}
+
+func TestMethodBacktrack(t *testing.T) {
+	r := newTestRouter(
+		Route{Path: "/users/me", Method: GET, Handler: noopHandler},
+		Route{Path: "/users/{id}", Method: POST, Handler: noopHandler},
+		Route{Path: "/files/readme", Method: GET, Handler: noopHandler},
+		Route{Path: "/files/{path*}", Method: DELETE, Handler: noopHandler},
+		Route{Path: "/raw/latest", Method: GET, Handler: noopHandler},
+		Route{Path: `/raw/\w+`, Method: PUT, Handler: noopHandler},
+	)
+	runMatchCases(t, r, []matchCase{
+		{name: "static", path: "/users/me", want: "/users/me"},
+		{name: "param after static", method: "POST", path: "/users/me", want: "/users/{id}", params: map[string]any{"id": "me"}},
+		{name: "catch-all after static", method: "DELETE", path: "/files/readme", want: "/files/{path*}"},
+		{name: "regex after tree", method: "PUT", path: "/raw/latest", want: `/raw/\w+`},
+		{name: "merged allow", method: "PATCH", path: "/users/me", allow: []string{"GET", "HEAD", "OPTIONS", "POST"}},
+	})
+}
+
+func TestMethodResponse(t *testing.T) {
+	r := newTestRouter(
+		Route{Path: "/users", Method: GET, Handler: func(w *handler.Response, r handler.Request) any {
+			return "users"
+		}},
+		Route{Path: "/users", Method: POST, Handler: noopHandler},
+		Route{Path: "/custom", Method: OPTIONS, Handler: func(w *handler.Response, r handler.Request) any {
+			w.SetHeader("X-Custom", "1")
+			return nil
+		}},
+	)
+	cases := []struct {
+		name   string
+		method string
+		path   string
+		status int
+		allow  string
+		header string
+	}{
+		{name: "head", method: http.MethodHead, path: "/users", status: http.StatusOK},
+		{name: "auto options", method: http.MethodOptions, path: "/users", status: http.StatusNoContent, allow: "GET, HEAD, OPTIONS, POST"},
+		{name: "method not allowed", method: http.MethodDelete, path: "/users", status: http.StatusMethodNotAllowed, allow: "GET, HEAD, OPTIONS, POST"},
+		{name: "custom options", method: http.MethodOptions, path: "/custom", status: http.StatusOK, header: "1"},
+		{name: "not found", method: http.MethodOptions, path: "/missing", status: http.StatusNotFound},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r.GetMux().ServeHTTP(w, httptest.NewRequest(tc.method, tc.path, nil))
+			if w.Code != tc.status {
+				t.Fatalf("%s %s: status = %d, want %d", tc.method, tc.path, w.Code, tc.status)
+			}
+			if got := w.Header().Get("Allow"); got != tc.allow {
+				t.Fatalf("%s %s: Allow = %q, want %q", tc.method, tc.path, got, tc.allow)
+			}
+			if got := w.Header().Get("X-Custom"); got != tc.header {
+				t.Fatalf("%s %s: X-Custom = %q, want %q", tc.method, tc.path, got, tc.header)
+			}
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/shi-yunsheng/gostar/router/handler"
+)
+
+// 请求体大小限制中间件，maxSize小于等于0时不限制
+func BodyLimitMiddleware(maxSize int64) Middleware {
+	return BodyLimitMiddlewareFunc(func() int64 {
+		return maxSize
+	})
+}
+
+// 请求体大小限制中间件，每次请求时通过getMaxSize获取最大大小，可用于动态更新限制
+// Content-Length超出限制时直接返回413，否则使用http.MaxBytesReader包装请求体，
+// 读取超出限制时GetAllBody等方法返回*http.MaxBytesError，可使用handler.IsRequestEntityTooLarge判断，
+// 处理器忽略该错误（如GetFile）且未写入响应时同样返回413
+func BodyLimitMiddlewareFunc(getMaxSize func() int64) Middleware {
+	return func(next handler.Handler) handler.Handler {
+		return func(w *handler.Response, r handler.Request) any {
+			maxSize := getMaxSize()
+			if maxSize <= 0 || r.Body == nil || r.Body == http.NoBody || r.IsWebsocket() {
+				return next(w, r)
+			}
+
+			if r.ContentLength > maxSize {
+				handler.RequestEntityTooLarge(w, r)
+				return nil
+			}
+			body := &limitedBody{ReadCloser: http.MaxBytesReader(w, r.Body, maxSize)}
+			r.Body = body
+
+			result := next(w, r)
+			if body.exceeded && !w.Written {
+				handler.RequestEntityTooLarge(w, r)
+				return nil
+			}
+			return result
+		}
+	}
+}
+
+// 记录读取是否超出大小限制的请求体
+type limitedBody struct {
+	io.ReadCloser
+	exceeded bool
+}
+
+// 读取请求体
+func (b *limitedBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if handler.IsRequestEntityTooLarge(err) {
+		b.exceeded = true
+	}
+	return n, err
+}
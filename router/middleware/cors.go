@@ -10,6 +10,13 @@ import (
 
 // CORS中间件
 func CORSMiddleware(allowedOrigins []string) Middleware {
+	return CORSMiddlewareFunc(func() []string {
+		return allowedOrigins
+	})
+}
+
+// CORS中间件，每次请求时通过getAllowedOrigins获取允许的来源，可用于动态更新允许的来源
+func CORSMiddlewareFunc(getAllowedOrigins func() []string) Middleware {
 	defaultAllowedHeaders := []string{
 		"Content-Type",
 		"Authorization",
@@ -27,6 +34,7 @@ func CORSMiddleware(allowedOrigins []string) Middleware {
 			}
 
 			// 检查是否允许该来源
+			allowedOrigins := getAllowedOrigins()
 			allowed := false
 			if len(allowedOrigins) == 0 || allowedOrigins[0] == "*" {
 				allowed = true
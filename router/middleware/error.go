@@ -3,7 +3,6 @@ package middleware
 import (
 	"fmt"
 
-	"github.com/shi-yunsheng/gostar/logger"
 	"github.com/shi-yunsheng/gostar/router/handler"
 )
 
@@ -12,7 +11,7 @@ func ErrorMiddleware(next handler.Handler) handler.Handler {
 	return func(w *handler.Response, r handler.Request) any {
 		defer func() {
 			if err := recover(); err != nil {
-				logger.E("Error: %v", err)
+				r.GetLogger().E("Error: %v", err)
 				handler.InternalServerError(w, r, fmt.Errorf("internal server error: %v", err))
 			}
 		}()
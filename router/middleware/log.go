@@ -4,7 +4,6 @@ import (
 	"fmt"
 	"time"
 
-	"github.com/shi-yunsheng/gostar/logger"
 	"github.com/shi-yunsheng/gostar/router/handler"
 )
 
@@ -27,41 +26,43 @@ func LogMiddleware(next handler.Handler) handler.Handler {
 		} else {
 			clientIP = "unknown"
 		}
+		// 使用当前应用实例的日志器
+		log := r.GetLogger()
 		// 输出请求信息
-		logger.I("Request received: %s %s from IP: %s", method, path, clientIP)
+		log.I("Request received: %s %s from IP: %s", method, path, clientIP)
 		// 继续处理请求并返回结果
 		response := next(w, r)
 		// 计算请求处理时间
 		duration := time.Since(startTime)
 		// 慢请求警告
 		if duration > 3*time.Second {
-			logger.W("Slow request: %s %s - Duration: %v", method, path, duration)
+			log.W("Slow request: %s %s - Duration: %v", method, path, duration)
 		}
 		// 输出请求完成信息
 		switch {
 		case w.StatusCode >= 100 && w.StatusCode < 200:
 			// 1xx 信息性响应
-			logger.I("Request completed: %s %s - Status: %d - From IP: %s - Duration: %v",
+			log.I("Request completed: %s %s - Status: %d - From IP: %s - Duration: %v",
 				method, path, w.StatusCode, clientIP, duration)
 		case w.StatusCode >= 200 && w.StatusCode < 300:
 			// 2xx 成功
-			logger.S("Request completed: %s %s - Status: %d - From IP: %s - Duration: %v",
+			log.S("Request completed: %s %s - Status: %d - From IP: %s - Duration: %v",
 				method, path, w.StatusCode, clientIP, duration)
 		case w.StatusCode >= 300 && w.StatusCode < 400:
 			// 3xx 重定向
-			logger.I("Request completed: %s %s - Status: %d - From IP: %s - Duration: %v",
+			log.I("Request completed: %s %s - Status: %d - From IP: %s - Duration: %v",
 				method, path, w.StatusCode, clientIP, duration)
 		case w.StatusCode >= 400 && w.StatusCode < 500:
 			// 4xx 客户端错误
-			logger.W("Request completed: %s %s - Status: %d - From IP: %s - Duration: %v",
+			log.W("Request completed: %s %s - Status: %d - From IP: %s - Duration: %v",
 				method, path, w.StatusCode, clientIP, duration)
 		case w.StatusCode >= 500:
 			// 5xx 服务器错误
-			logger.E("Request completed: %s %s - Status: %d - From IP: %s - Duration: %v",
+			log.E("Request completed: %s %s - Status: %d - From IP: %s - Duration: %v",
 				method, path, w.StatusCode, clientIP, duration)
 		default:
 			// 未知状态码
-			logger.W("Request completed: %s %s - Status: %d - From IP: %s - Duration: %v",
+			log.W("Request completed: %s %s - Status: %d - From IP: %s - Duration: %v",
 				method, path, w.StatusCode, clientIP, duration)
 		}
 
@@ -7,8 +7,6 @@ import (
 	"time"
 
 	"github.com/shi-yunsheng/gostar/router/handler"
-
-	"github.com/shi-yunsheng/gostar/logger"
 )
 
 // 限流中间件
@@ -68,7 +66,7 @@ func RateLimitMiddleware(requests int, per time.Duration) Middleware {
 			// 检查是否超过速率限制
 			if len(active) >= requests {
 				mu.Unlock()
-				logger.W("Rate limit exceeded: IP=%s, Path=%s, Current requests=%d, Limit=%d", ip, path, len(active), requests)
+				r.GetLogger().W("Rate limit exceeded: IP=%s, Path=%s, Current requests=%d, Limit=%d", ip, path, len(active), requests)
 				w.WriteHeader(http.StatusTooManyRequests)
 				w.Write([]byte("Rate limit exceeded"))
 				return nil
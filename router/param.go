@@ -0,0 +1,199 @@
+package router
+
+import (
+	"fmt"
+	"regexp"
+	"regexp/syntax"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/shi-yunsheng/gostar/date"
+	"github.com/shi-yunsheng/gostar/utils"
+)
+
+// 路径参数类型
+type paramType struct {
+	// 匹配参数值的正则表达式，不包含捕获组
+	pattern string
+	// 将参数值转换为对应类型
+	convert func(string) (any, error)
+}
+
+var (
+	paramTypesMu sync.RWMutex
+	// 已注册的路径参数类型，键为类型名称
+	paramTypes = map[string]paramType{
+		"str":       {pattern: `[^/]+`, convert: convertString},
+		"int":       {pattern: `-?\d+`, convert: convertInt},
+		"float":     {pattern: `-?\d+(?:\.\d+)?`, convert: convertFloat},
+		"bool":      {pattern: `true|false|True|False|TRUE|FALSE|1|0`, convert: convertBool},
+		"date":      {pattern: `\d{4}-(?:1[0-2]|0?[1-9])-(?:3[01]|[12][0-9]|0?[1-9])(?:[T_\s](?:[01][0-9]|2[0-3]):[0-5][0-9]:[0-5][0-9])?`, convert: convertDate},
+		"uuid":      {pattern: `[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}`, convert: convertString},
+		"slug":      {pattern: `[a-z0-9]+(?:-[a-z0-9]+)*`, convert: convertString},
+		"snowflake": {pattern: `\d{1,19}`, convert: convertSnowflake},
+		"hex":       {pattern: `[0-9a-fA-F]+`, convert: convertString},
+	}
+	// 路径参数类型名称格式
+	paramTypeNameRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+)
+
+// 注册路径参数类型，pattern为匹配参数值的正则表达式，其中的捕获组会转换为非捕获组，
+// convert将参数值转换为对应类型，为nil时参数值为字符串。已存在的类型会被覆盖
+// 注册后可以在路径中使用，如：RegisterParamType("version", `v\d+`, nil)后使用/api/{v:version}
+func RegisterParamType(name, pattern string, convert func(string) (any, error)) {
+	if !paramTypeNameRegex.MatchString(name) || name == "enum" || name == "re" {
+		panic("invalid path parameter type name: " + name)
+	}
+	pattern, err := nonCapturing(pattern)
+	if err != nil {
+		panic("invalid path parameter type pattern: " + err.Error())
+	}
+	if convert == nil {
+		convert = convertString
+	}
+
+	paramTypesMu.Lock()
+	defer paramTypesMu.Unlock()
+	paramTypes[name] = paramType{pattern: pattern, convert: convert}
+}
+
+// 获取路径参数类型，支持已注册的类型、枚举enum(a|b|c)和正则约束re(...)
+func lookupParamType(name string) (paramType, bool) {
+	if options, ok := cutFunc(name, "enum"); ok {
+		values := strings.Split(options, "|")
+		for i, value := range values {
+			values[i] = regexp.QuoteMeta(value)
+		}
+		return paramType{pattern: "(?:" + strings.Join(values, "|") + ")", convert: convertString}, true
+	}
+	if pattern, ok := cutFunc(name, "re"); ok {
+		pattern, err := nonCapturing(pattern)
+		if err != nil {
+			panic("invalid path parameter pattern: " + err.Error())
+		}
+		return paramType{pattern: "(?:" + pattern + ")", convert: convertString}, true
+	}
+
+	paramTypesMu.RLock()
+	defer paramTypesMu.RUnlock()
+	typ, ok := paramTypes[name]
+	return typ, ok
+}
+
+// 解析name(args)形式的类型，返回括号中的内容
+func cutFunc(value, name string) (string, bool) {
+	after, ok := strings.CutPrefix(value, name+"(")
+	if !ok || !strings.HasSuffix(after, ")") {
+		return "", false
+	}
+	return strings.TrimSuffix(after, ")"), true
+}
+
+// 将正则表达式中的捕获组转换为非捕获组，避免影响路径参数的捕获顺序
+func nonCapturing(pattern string) (string, error) {
+	re, err := syntax.Parse(pattern, syntax.Perl)
+	if err != nil {
+		return "", err
+	}
+	return stripCaptures(re).String(), nil
+}
+
+// 移除正则表达式语法树中的捕获组
+func stripCaptures(re *syntax.Regexp) *syntax.Regexp {
+	for i, sub := range re.Sub {
+		re.Sub[i] = stripCaptures(sub)
+	}
+	if re.Op == syntax.OpCapture {
+		return re.Sub[0]
+	}
+	return re
+}
+
+// 查找路径中的路径参数，返回每个参数{...}的起止位置，参数中括号内的内容可以包含{}和:，如：{code:re([A-Z]{3})}
+func findParams(path string) [][]int {
+	indices := make([][]int, 0)
+	start, depth := -1, 0
+	for i := 0; i < len(path); i++ {
+		switch c := path[i]; {
+		case start >= 0 && depth > 0 && c == '\\':
+			// 跳过转义字符
+			i++
+		case c == '{' && depth == 0:
+			start = i
+		case start >= 0 && c == '(':
+			depth++
+		case start >= 0 && c == ')' && depth > 0:
+			depth--
+		case start >= 0 && c == '}' && depth == 0:
+			if i > start+1 {
+				indices = append(indices, []int{start, i + 1})
+			}
+			start = -1
+		}
+	}
+	return indices
+}
+
+// 拆分路径参数的名称、类型和默认值，只拆分括号外的:
+func splitParam(param string) []string {
+	parts := make([]string, 0, 3)
+	last, depth := 0, 0
+	for i := 0; i < len(param); i++ {
+		switch param[i] {
+		case '\\':
+			i++
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ':':
+			if depth == 0 {
+				parts = append(parts, param[last:i])
+				last = i + 1
+			}
+		}
+	}
+	return append(parts, param[last:])
+}
+
+// 将参数值转换为字符串
+func convertString(value string) (any, error) {
+	return value, nil
+}
+
+// 将参数值转换为整数
+func convertInt(value string) (any, error) {
+	return strconv.ParseInt(value, 10, 64)
+}
+
+// 将参数值转换为浮点数
+func convertFloat(value string) (any, error) {
+	return strconv.ParseFloat(value, 64)
+}
+
+// 将参数值转换为布尔值
+func convertBool(value string) (any, error) {
+	return strconv.ParseBool(value)
+}
+
+// 将参数值转换为日期，支持日期格式和时间戳
+func convertDate(value string) (any, error) {
+	val, err := date.ParseTimeString(value)
+	if err != nil {
+		val, err = date.ParseTimestamp(value)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return val, nil
+}
+
+// 将参数值解析为雪花ID
+func convertSnowflake(value string) (any, error) {
+	id, err := utils.ParseSnowflakeID(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid snowflake id: %w", err)
+	}
+	return id, nil
+}
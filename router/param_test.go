@@ -0,0 +1,90 @@
+package router
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/shi-yunsheng/gostar/utils"
+)
+
+func TestParamConstraints(t *testing.T) {
+	r := newTestRouter(
+		Route{Path: "/files/{kind:enum(image|video|a.b)}", Handler: noopHandler},
+		Route{Path: "/codes/{code:re([A-Z]{3})}", Handler: noopHandler},
+		Route{Path: "/tags/{tag:re((foo|bar)-\\d+)}/{id:int}", Handler: noopHandler},
+		Route{Path: "/posts/{slug:slug}", Handler: noopHandler},
+		Route{Path: "/objects/{id:uuid}", Handler: noopHandler},
+	)
+	runMatchCases(t, r, []matchCase{
+		{name: "enum", path: "/files/video", want: "/files/{kind:enum(image|video|a.b)}", params: map[string]any{"kind": "video"}},
+		{name: "enum quoted", path: "/files/a.b", want: "/files/{kind:enum(image|video|a.b)}", params: map[string]any{"kind": "a.b"}},
+		{name: "enum miss", path: "/files/audio"},
+		{name: "enum not regex", path: "/files/axb"},
+		{name: "enum partial", path: "/files/images"},
+		{name: "regex", path: "/codes/ABC", want: "/codes/{code:re([A-Z]{3})}", params: map[string]any{"code": "ABC"}},
+		{name: "regex lower", path: "/codes/abc"},
+		{name: "regex long", path: "/codes/ABCD"},
+		{name: "regex group", path: "/tags/bar-12/7", want: "/tags/{tag:re((foo|bar)-\\d+)}/{id:int}", params: map[string]any{"tag": "bar-12", "id": int64(7)}},
+		{name: "regex group miss", path: "/tags/baz-12/7"},
+		{name: "slug", path: "/posts/hello-world", want: "/posts/{slug:slug}", params: map[string]any{"slug": "hello-world"}},
+		{name: "slug miss", path: "/posts/Hello_World"},
+		{name: "uuid", path: "/objects/123e4567-e89b-12d3-a456-426614174000", want: "/objects/{id:uuid}"},
+		{name: "uuid miss", path: "/objects/123e4567"},
+	})
+}
+
+func TestParamConversionFailure(t *testing.T) {
+	r := newTestRouter(
+		Route{Path: "/ids/{id:snowflake}", Handler: noopHandler},
+		Route{Path: "/users/{id:int}", Handler: noopHandler},
+		Route{Path: "/pages/{page?:int:1}", Handler: noopHandler},
+		Route{Path: `/raw/{n:int}/\d+`, Handler: noopHandler},
+		Route{Path: "/items/{id:int}", Handler: noopHandler},
+		Route{Path: "/items/{name}", Handler: noopHandler},
+		Route{Path: `/codes/{n:int}/\w+`, Handler: noopHandler},
+		Route{Path: `/codes/\d+/{s}`, Handler: noopHandler},
+	)
+	runMatchCases(t, r, []matchCase{
+		{name: "snowflake", path: "/ids/9223372036854775807", want: "/ids/{id:snowflake}", params: map[string]any{"id": mustSnowflake(t, "9223372036854775807")}},
+		{name: "snowflake overflow", path: "/ids/9999999999999999999"},
+		{name: "int overflow", path: "/users/99999999999999999999"},
+		{name: "optional int overflow", path: "/pages/99999999999999999999"},
+		{name: "regex route", path: "/raw/12/3", want: `/raw/{n:int}/\d+`, params: map[string]any{"n": int64(12)}},
+		{name: "regex route overflow", path: "/raw/99999999999999999999/3"},
+		{name: "int", path: "/items/12", want: "/items/{id:int}", params: map[string]any{"id": int64(12)}},
+		{name: "overflow falls back to string", path: "/items/99999999999999999999999", want: "/items/{name}", params: map[string]any{"name": "99999999999999999999999"}},
+		{name: "regex overflow falls back", path: "/codes/99999999999999999999/x", want: `/codes/\d+/{s}`, params: map[string]any{"s": "x"}},
+	})
+}
+
+func TestRegisterParamType(t *testing.T) {
+	RegisterParamType("testversion", `v(\d+)`, func(value string) (any, error) {
+		return strconv.Atoi(value[1:])
+	})
+	r := newTestRouter(Route{Path: "/api/{v:testversion}/{id:int}", Handler: noopHandler})
+	runMatchCases(t, r, []matchCase{
+		{name: "custom", path: "/api/v2/5", want: "/api/{v:testversion}/{id:int}", params: map[string]any{"v": 2, "id": int64(5)}},
+		{name: "custom miss", path: "/api/2/5"},
+	})
+
+	for _, name := range []string{"enum", "re", "1abc", "a-b"} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("RegisterParamType(%q) did not panic", name)
+				}
+			}()
+			RegisterParamType(name, `\d+`, nil)
+		}()
+	}
+}
+
+// 解析雪花ID，失败时终止测试
+func mustSnowflake(t *testing.T, value string) utils.SnowflakeID {
+	t.Helper()
+	id, err := utils.ParseSnowflakeID(value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return id
+}
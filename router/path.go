@@ -2,17 +2,16 @@ package router
 
 import (
 	"regexp"
+	"slices"
 	"sort"
-	"strconv"
 	"strings"
 
-	"github.com/shi-yunsheng/gostar/date"
 	"github.com/shi-yunsheng/gostar/router/handler"
 	"github.com/shi-yunsheng/gostar/router/middleware"
 )
 
 // 解析路由
-func (r *Router) parseRoute(routes []Route, parent string) {
+func (r *Router) parseRoute(routes []Route, parent *Route) {
 	for i := range routes {
 		route := &routes[i]
 		// 如果Webapp和Static同时设置，则抛出错误
@@ -27,18 +26,17 @@ func (r *Router) parseRoute(routes []Route, parent string) {
 		if !strings.HasPrefix(route.Path, "/") {
 			route.Path = "/" + route.Path
 		}
+		// 模板路径，包含父路由的原始路径，用于构建路由树
+		route.template = route.Path
+		route.parent = parent
 		// 排除"/"，否则会和根路径冲突
-		if parent != "/" {
-			route.parent = parent
+		if parent != nil && parent.Path != "/" {
+			route.template = parent.template + route.Path
 			// 移除父路径的^和$
-			if after, ok := strings.CutPrefix(parent, "^"); ok {
-				parent = after
-			}
-			if after, ok := strings.CutSuffix(parent, "$"); ok {
-				parent = after
-			}
+			prefix := strings.TrimPrefix(parent.Path, "^")
+			prefix = strings.TrimSuffix(prefix, "$")
 
-			route.Path = parent + route.Path
+			route.Path = prefix + route.Path
 		}
 
 		route.Path, route.params = r.parsePath(route.Path)
@@ -62,11 +60,31 @@ func (r *Router) parseRoute(routes []Route, parent string) {
 				route.Path = route.Path + `(/[^/]+.*)?$`
 			}
 		}
+		if route.Bind != nil {
+			route.modelType = bindModelType(route.Bind)
+		}
+		// 子路由的名称加上父路由名称前缀
+		if route.Name != "" && route.parent != nil && route.parent.Name != "" {
+			route.Name = parent.Name + "." + route.Name
+		}
+		// 域名为空时继承父路由
+		if route.Host == "" && route.parent != nil {
+			route.Host = route.parent.Host
+		}
+		// 允许的HTTP方法，为空时继承父路由
+		route.methods = route.Method.split()
+		if len(route.methods) == 0 && route.parent != nil {
+			route.methods = route.parent.methods
+		}
 		// 存储路由
-		r.routes[route.Path] = route
-		// 合并父路由的配置（SecretKey和Middleware）
-		if route.parent != "" && r.routes[route.parent] != nil {
-			parentRoute := r.routes[route.parent]
+		r.storeRoute(route)
+		// 合并父路由的配置（Admin、SecretKey和Middleware）
+		if route.parent != nil {
+			parentRoute := route.parent
+			// 继承父路由的管理路由设置
+			if parentRoute.Admin {
+				route.Admin = true
+			}
 			// 从父路由合并SecretKey到子路由
 			if parentRoute.SecretKey != nil {
 				if route.SecretKey == nil {
@@ -89,7 +107,7 @@ func (r *Router) parseRoute(routes []Route, parent string) {
 		}
 		// 如果静态文件或网站设置为空，则解析子路由
 		if route.Static == nil && route.Webapp == nil && len(route.Children) > 0 {
-			r.parseRoute(route.Children, route.Path)
+			r.parseRoute(route.Children, route)
 		}
 	}
 }
@@ -101,126 +119,31 @@ func (r *Router) parsePath(path string) (string, []handler.Param) {
 		return path, nil
 	}
 	// 匹配路径参数
-	re := regexp.MustCompile(`\{([^:{}]+)(?::([^:{}]+))?(?::([^:{}]+))?\}`)
-	allMatches := re.FindAllStringSubmatch(path, -1)
+	matchIndices := findParams(path)
 	// 如果没有找到匹配，返回原始路径
-	if len(allMatches) == 0 {
+	if len(matchIndices) == 0 {
 		return path, nil
 	}
 	// 存储当前路径的所有参数信息
 	params := make([]handler.Param, 0)
 
-	for _, matches := range allMatches {
-		// 移除matches中的空元素
-		cleanMatches := make([]string, 0, len(matches))
-		for _, m := range matches {
-			if m != "" {
-				cleanMatches = append(cleanMatches, m)
+	for _, matchIdx := range matchIndices {
+		param := newParam(path[matchIdx[0]+1:matchIdx[1]-1], path)
+		// 通配参数必须是字符串类型，并且是路径的最后一段
+		if param.CatchAll {
+			if param.Type != "str" {
+				panic("invalid catch-all path parameter type, must be str. Got: " + param.Type)
 			}
-		}
-		matches = cleanMatches
-		// 匹配的数量不正确
-		if len(matches) > 4 || len(matches) < 2 {
-			panic("invalid path parameter format, must be {param[:type:default]}, e.g.: {id}, {page:int:1}, {cid:323}. Got: " + path)
-		}
-
-		var (
-			param        = matches[1]
-			typer        string
-			defaultValue any
-			optional     bool
-		)
-		// 如果参数名以?结尾，则认为该参数是可选参数
-		if strings.HasSuffix(param, "?") {
-			optional = true
-			param = strings.TrimSuffix(param, "?")
-		}
-
-		switch len(matches) {
-		case 2:
-			typer = "str"
-		case 3:
-			switch matches[2] {
-			case "int", "float", "str", "bool", "date":
-				typer = matches[2]
-			default:
-				typer = "str"
-				defaultValue = matches[2]
-			}
-		case 4:
-			switch matches[2] {
-			case "int", "float", "str", "bool", "date":
-				typer = matches[2]
-				if matches[3] != "" {
-					switch typer {
-					case "int":
-						val, err := strconv.ParseInt(matches[3], 10, 64)
-						if err != nil {
-							// 路径参数默认值无效，必须为整数
-							panic("invalid path parameter default value, must be int. Got: " + matches[3])
-						}
-						defaultValue = val
-					case "float":
-						val, err := strconv.ParseFloat(matches[3], 64)
-						if err != nil {
-							// 路径参数默认值无效，必须为浮点数
-							panic("invalid path parameter default value, must be float. Got: " + matches[3])
-						}
-						defaultValue = val
-					case "bool":
-						val, err := strconv.ParseBool(matches[3])
-						if err != nil {
-							// 路径参数默认值无效，必须为布尔值
-							panic("invalid path parameter default value, must be bool. Got: " + matches[3])
-						}
-						defaultValue = val
-					case "date":
-						val, err := date.ParseTimeString(matches[3])
-						if err != nil {
-							val, err = date.ParseTimestamp(matches[3])
-							if err != nil {
-								// 路径参数默认值无效，必须为日期格式（如：2025-09-20 20:18:03、2025-09-20）或时间戳（如：1640995200）
-								panic("invalid path parameter default value, must be date (e.g.: 2025-09-20 20:18:03 or 2025-09-20) or timestamp (e.g.: 1640995200). Got: " + matches[3])
-							}
-						}
-						defaultValue = val
-					default:
-						defaultValue = matches[3]
-					}
-				}
-			default:
-				// 路径参数类型无效，必须为int, float, str, bool, date
-				panic("invalid path parameter type, must be int, float, str, bool, date. Got: " + matches[2])
+			if matchIdx[0] == 0 || path[matchIdx[0]-1] != '/' || matchIdx[1] != len(path) {
+				panic("catch-all path parameter must be the last segment of the path, e.g.: /files/{path*}. Got: " + path)
 			}
+			param.Pattern = `(.+)`
 		}
-
-		var pattern string
-		switch typer {
-		case "int":
-			pattern = `(\d+)`
-		case "float":
-			pattern = `(\d+\.\d+)`
-		case "bool":
-			pattern = `(true|false|True|False|TRUE|FALSE|1|0)`
-		case "date":
-			pattern = `(\d{4}-(?:1[0-2]|0?[1-9])-(?:3[01]|[12][0-9]|0?[1-9])(?:[T_\s](?:[01][0-9]|2[0-3]):[0-5][0-9]:[0-5][0-9])?)`
-		default:
-			pattern = `([^/]+)`
-		}
-
-		params = append(params, handler.Param{
-			Key:      param,
-			Type:     typer,
-			Default:  defaultValue,
-			Pattern:  pattern,
-			Optional: optional,
-		})
+		params = append(params, param)
 	}
 	// 使用正则表达式替换所有参数
 	resultBuilder := strings.Builder{}
 	lastIndex := 0
-	matchIndices := re.FindAllStringSubmatchIndex(path, -1)
-
 	for idx, matchIdx := range matchIndices {
 		start := matchIdx[0]
 		end := matchIdx[1]
@@ -262,13 +185,148 @@ func (r *Router) parsePath(path string) (string, []handler.Param) {
 	return resultPath, params
 }
 
-// 按路径长度排序路由（最长的在前）
-func (r *Router) sortRoutes() {
-	for path := range r.routes {
-		r.sortedRoutes = append(r.sortedRoutes, path)
+// 解析路径参数，token为{}中的内容，格式为param[:type[:default]]，path用于错误信息
+func newParam(token string, path string) handler.Param {
+	parts := splitParam(token)
+	// 匹配的数量不正确
+	if len(parts) > 3 || parts[0] == "" {
+		panic("invalid path parameter format, must be {param[:type:default]}, e.g.: {id}, {page:int:1}, {cid:323}. Got: " + path)
+	}
+
+	var (
+		param        = parts[0]
+		typer        = "str"
+		defaultValue any
+		optional     bool
+		catchAll     bool
+	)
+	// 如果参数名以?结尾，则认为该参数是可选参数，以*结尾则认为该参数是通配参数，两者可以同时使用，如：{path*?}
+	for {
+		if after, ok := strings.CutSuffix(param, "?"); ok {
+			optional, param = true, after
+		} else if after, ok := strings.CutSuffix(param, "*"); ok {
+			catchAll, param = true, after
+		} else {
+			break
+		}
+	}
+
+	typ, _ := lookupParamType(typer)
+	switch len(parts) {
+	case 2:
+		// 不是参数类型时视为默认值
+		if t, ok := lookupParamType(parts[1]); ok {
+			typer, typ = parts[1], t
+		} else {
+			defaultValue = parts[1]
+		}
+	case 3:
+		t, ok := lookupParamType(parts[1])
+		if !ok {
+			// 路径参数类型无效，必须为已注册的类型
+			panic("invalid path parameter type, must be a registered type (int, float, str, bool, date, uuid, slug, snowflake, hex), enum(...) or re(...). Got: " + parts[1])
+		}
+		typer, typ = parts[1], t
+		if parts[2] != "" {
+			val, err := typ.convert(parts[2])
+			if err != nil {
+				// 路径参数默认值无效，必须为参数类型的值
+				panic("invalid path parameter default value, must be " + typer + ". Got: " + parts[2])
+			}
+			defaultValue = val
+		}
+	}
+
+	return handler.Param{
+		Key:      param,
+		Type:     typer,
+		Default:  defaultValue,
+		Pattern:  "(" + typ.pattern + ")",
+		Optional: optional,
+		CatchAll: catchAll,
+	}
+}
+
+// 存储路由，同一路径和HTTP方法只能注册一个路由，路由名称不能重复，只包含子路由的分组路由不参与匹配
+func (r *Router) storeRoute(route *Route) {
+	keys := make([]RouteKey, 0, len(route.methods))
+	for _, method := range route.methods {
+		keys = append(keys, RouteKey{Host: route.Host, Path: route.Path, Method: method})
+	}
+	if len(keys) == 0 {
+		keys = append(keys, RouteKey{Host: route.Host, Path: route.Path})
+	}
+
+	if route.Name != "" {
+		if _, ok := r.names[route.Name]; ok {
+			panic("route name already exists: " + route.Name)
+		}
+		r.names[route.Name] = route
+	}
+
+	group := isGroupRoute(route)
+	for _, key := range keys {
+		if exists, ok := r.routes[key]; ok {
+			if group {
+				continue
+			}
+			if !isGroupRoute(exists) {
+				method := key.Method
+				if method == "" {
+					method = "ANY"
+				}
+				panic("route already exists: " + string(method) + " " + key.Host + key.Path)
+			}
+		}
+		r.routes[key] = route
+	}
+	if !group {
+		r.addToTree(route)
 	}
+}
+
+// 判断是否为只包含子路由的分组路由
+func isGroupRoute(route *Route) bool {
+	return route.Handler == nil && route.Webapp == nil && route.Static == nil && !route.Websocket
+}
 
-	sort.Slice(r.sortedRoutes, func(i, j int) bool {
-		return len(r.sortedRoutes[i]) > len(r.sortedRoutes[j])
+// 将路由加入路由树，设置了域名的路由加入对应域名的路由树，包含正则表达式的路由只能使用正则匹配
+func (r *Router) addToTree(route *Route) {
+	table := r.routeTable
+	if route.Host != "" {
+		host := r.hostTable(route.Host)
+		// 域名参数和路径参数合并后按名称获取，名称不能重复
+		_, params := r.parsePath(route.template)
+		for _, param := range params {
+			if slices.ContainsFunc(host.params, func(p handler.Param) bool { return p.Key == param.Key }) {
+				panic("path parameter name conflicts with host parameter: " + param.Key + ". Got: " + route.Host + route.template)
+			}
+		}
+		table = host.routeTable
+	}
+	if isRegexTemplate(route.template) {
+		route.regex = regexp.MustCompile(route.Path)
+		table.regexRoutes = append(table.regexRoutes, route)
+		return
+	}
+
+	_, route.allParams = r.parsePath(route.template)
+	segments := splitTemplate(route.template, route.allParams)
+	catchAll := route.Static != nil || route.Webapp != nil
+	requireSegment := route.Static != nil && !route.Static.AllowDir
+	table.tree.insert(segments, route, nil, catchAll, requireSegment)
+}
+
+// 按路径长度排序正则路由（最长的在前），不包含参数的域名优先匹配
+func (r *Router) sortRoutes() {
+	r.routeTable.sortRoutes()
+	for _, table := range r.hosts {
+		table.sortRoutes()
+	}
+	sort.SliceStable(r.hosts, func(i, j int) bool {
+		if len(r.hosts[i].params) != len(r.hosts[j].params) {
+			return len(r.hosts[i].params) < len(r.hosts[j].params)
+		}
+		return len(r.hosts[i].host) > len(r.hosts[j].host)
 	})
 }
@@ -5,9 +5,11 @@ import (
 	"errors"
 	"net/http"
 	"reflect"
-	"sync"
+	"regexp"
+	"slices"
+	"strings"
 
-	"github.com/go-playground/validator/v10"
+	"github.com/shi-yunsheng/gostar/i18n"
 	"github.com/shi-yunsheng/gostar/router/handler"
 	"github.com/shi-yunsheng/gostar/router/middleware"
 
@@ -27,18 +29,61 @@ const (
 	HEAD    Method = "HEAD"
 )
 
+// 组合多个HTTP方法，用于路由的Method，如：Methods(GET, POST)
+func Methods(methods ...Method) Method {
+	values := make([]string, len(methods))
+	for i, method := range methods {
+		values[i] = string(method)
+	}
+	return Method(strings.Join(values, ","))
+}
+
+// 拆分HTTP方法，多个方法用逗号分隔，如：GET,POST
+func (m Method) split() []Method {
+	methods := make([]Method, 0)
+	for value := range strings.SplitSeq(string(m), ",") {
+		value = strings.ToUpper(strings.TrimSpace(value))
+		if value != "" && !slices.Contains(methods, Method(value)) {
+			methods = append(methods, Method(value))
+		}
+	}
+	return methods
+}
+
+// 路由表的键，同一路径可以为不同的HTTP方法和域名注册路由
+type RouteKey struct {
+	// 域名模板，为空表示不限制
+	Host string
+	// 编译后的路径正则
+	Path string
+	// HTTP方法，为空表示不限制
+	Method Method
+}
+
 // 路由配置
 type Route struct {
-	// 允许的HTTP方法，默认不限制
+	// 允许的HTTP方法，默认不限制，多个方法用逗号分隔或使用Methods组合，如：Methods(GET, POST)
+	// 同一路径可以为不同的方法分别注册路由，GET路由会自动响应HEAD请求，未注册OPTIONS时会自动响应OPTIONS请求
 	Method Method
 	// 请求路径，支持正则表达式，路径参数。
 	// 正则表达式，例如：/user/\d+
 	// 路径参数需要用{}包裹，例如：/user/{id}，支持多个路径参数和路径嵌套，例如：/user/{id}/detail/{girlfriend}
-	// 路径参数可以指定默认值或类型，格式为：{param:type:default}或{param:default}，例如：/user/{id:int}和/user/list/{page:int:1}，支持类型：int, float, str, bool, date, default: str
+	// 路径参数可以指定默认值或类型，格式为：{param:type:default}或{param:default}，例如：/user/{id:int}和/user/list/{page:int:1}，
+	// 支持类型：int, float, str, bool, date, uuid, slug, snowflake, hex以及通过RegisterParamType注册的类型，default: str
+	// 也可以使用枚举或正则约束，例如：/order/{status:enum(paid|refunded)}和/currency/{code:re([A-Z]{3})}
 	// 路径参数支持可选，参数名使用?结尾表示可选，例如：/user/list/{page?:int}
+	// 路径参数名使用*结尾表示通配参数，匹配剩余的全部路径（包含/），只能作为路径的最后一段，例如：/files/{path*}和/files/{path*?:index.html}
 	Path string
+	// 路由名称，用于通过Router.URL生成URL，子路由的名称会自动加上父路由名称前缀，如：users.show
+	Name string
+	// 域名，支持路径参数格式的参数，如：{tenant}.example.com，不包含端口，域名参数可以通过GetParam获取，
+	// 子路由会继承父路由的设置。设置了域名的路由优先匹配，不设置域名的路由用于域名路由中没有匹配的请求
+	Host string
 	// 认证密钥，如果设置，则请求头中必须包含该密钥，否则会返回401错误，例如：{"secret": "aha~"}
 	SecretKey map[string]string
+	// 是否为管理路由，配置了管理监听地址（admin_bind）时，管理路由只在管理地址上提供，其他路由只在普通地址上提供，
+	// 子路由会继承父路由的设置
+	Admin bool
 	// 请求处理函数
 	Handler handler.Handler
 	// 子路由
@@ -58,30 +103,42 @@ type Route struct {
 	Webapp *handler.Webapp
 	// 路径参数
 	params []handler.Param
-	// 父路径
-	parent string
+	// 父路由
+	parent *Route
+	// 允许的HTTP方法（为空时继承父路由），为空表示不限制
+	methods []Method
+	// 模板路径，包含父路由的原始路径，如：/user/{id:int}/detail
+	template string
+	// 模板路径中的所有路径参数（包含父路由的参数）
+	allParams []handler.Param
+	// 编译后的路径正则，只有包含正则表达式的路由才会设置
+	regex *regexp.Regexp
 	// 模型，可以实现"Validate()"接口，如果有"Validate"接口，则优先使用"Validate"接口进行校验，
 	// "Validate()"接口可以返回"error"或"any"，如果返回"any"，则返回的any会被作为响应体返回。
 	// 否则使用 github.com/go-playground/validator/v10 进行校验，有关validator的用法请参考 https://github.com/go-playground/validator
 	Bind any
-	// 确保模型类型只初始化一次
-	once sync.Once
-	// 缓存模型类型
+	// 缓存模型类型，解析路由时初始化
 	modelType reflect.Type
 }
 
+// 获取绑定模型的类型
+func bindModelType(bind any) reflect.Type {
+	t := reflect.TypeOf(bind)
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t
+}
+
 // 验证绑定参数
 func (r *Route) Validate(req *handler.Request) (any, error) {
-	// 初始化模型类型
-	r.once.Do(func() {
-		t := reflect.TypeOf(r.Bind)
-		if t.Kind() == reflect.Pointer {
-			t = t.Elem()
-		}
-		r.modelType = t
-	})
+	// 未经过路由解析的路由，直接获取模型类型
+	modelType := r.modelType
+	if modelType == nil {
+		modelType = bindModelType(r.Bind)
+	}
 	// 创建模型实例
-	modelInstance := reflect.New(r.modelType).Interface()
+	modelInstance := reflect.New(modelType).Interface()
 	// 如果没有指定请求方式，则根据实际的请求方式进行绑定，POST、PUT、PATCH、DELETE请求方式绑定请求体，其他请求方式绑定查询参数
 	if r.Method == "" {
 		var model map[string]any
@@ -118,9 +175,8 @@ func (r *Route) Validate(req *handler.Request) (any, error) {
 				return resp, errors.New("validate failed")
 			}
 		} else {
-			// 模型没有实现"Validate() error"接口，使用github.com/go-playground/validator/v10进行校验
-			validate := validator.New()
-			err = validate.Struct(modelInstance)
+			// 模型没有实现"Validate() error"接口，使用github.com/go-playground/validator/v10进行校验，校验错误会按请求的语言翻译
+			err = i18n.Validator().Struct(modelInstance)
 			if err != nil {
 				return nil, err
 			}
@@ -133,10 +189,18 @@ func (r *Route) Validate(req *handler.Request) (any, error) {
 type Router struct {
 	// HTTP ServeMux实例
 	mux *http.ServeMux
-	// 路由表
-	routes map[string]*Route
-	// 排序后的路由
-	sortedRoutes []string
+	// 路由表，键为路径和HTTP方法
+	routes map[RouteKey]*Route
+	// 命名路由，键为路由名称
+	names map[string]*Route
+	// 不限制域名的路由
+	*routeTable
+	// 域名路由，请求域名匹配时优先使用
+	hosts []*hostTable
+	// 通过Group创建的顶层路由组
+	groups []*Group
+	// 是否已注册根路由处理器
+	handled bool
 	// 全局中间件，洋葱模型
 	middleware []middleware.Middleware
 	// 全局认证密钥，如果设置，则请求头中必须包含该密钥，否则会返回401错误，例如：{"secret": "aha~"}
@@ -150,6 +214,6 @@ func (r *Router) GetMux() *http.ServeMux {
 }
 
 // 获取路由表
-func (r *Router) GetRoutes() map[string]*Route {
+func (r *Router) GetRoutes() map[RouteKey]*Route {
 	return r.routes
 }
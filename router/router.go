@@ -2,6 +2,9 @@ package router
 
 import (
 	"net/http"
+	"slices"
+	"sort"
+	"strings"
 
 	"github.com/shi-yunsheng/gostar/router/handler"
 	"github.com/shi-yunsheng/gostar/router/middleware"
@@ -10,19 +13,25 @@ import (
 // 新建路由
 func NewRouter() *Router {
 	return &Router{
-		mux:          http.NewServeMux(),
-		routes:       make(map[string]*Route),
-		sortedRoutes: make([]string, 0),
-		middleware:   make([]middleware.Middleware, 0),
-		secretKey:    make(map[string]string),
+		mux:        http.NewServeMux(),
+		routes:     make(map[RouteKey]*Route),
+		names:      make(map[string]*Route),
+		routeTable: newRouteTable(),
+		middleware: make([]middleware.Middleware, 0),
+		secretKey:  make(map[string]string),
 	}
 }
 
-// 使用路由
+// 使用路由，可以多次使用
 func (r *Router) UseRoute(routes []Route) {
-	r.parseRoute(routes, "")
+	r.parseRoute(routes, nil)
 
 	r.sortRoutes()
+	// 多次使用路由时，根路由处理器只注册一次，全局中间件以第一次使用路由时为准
+	if r.handled {
+		return
+	}
+	r.handled = true
 
 	handleFunc := r.serveHTTP
 	// 加载全局中间件
@@ -45,3 +54,78 @@ func (r *Router) UseSecretKey(key string, value string) {
 	}
 	r.secretKey[key] = value
 }
+
+// 路由信息，用于展示路由表
+type RouteInfo struct {
+	// 域名模板，为空表示不限制
+	Host string `json:"host"`
+	// 编译后的路径正则
+	Path string `json:"path"`
+	// HTTP方法，为空表示不限制
+	Method string `json:"method"`
+	// 路由中间件数量（包含继承自父路由的中间件，不包含全局中间件）
+	Middleware int `json:"middleware"`
+	// 是否设置了认证密钥（包含继承自父路由和全局的认证密钥）
+	SecretKey bool `json:"secret_key"`
+	// 是否为管理路由
+	Admin bool `json:"admin"`
+	// 路由类型：handler, static, webapp, websocket, group
+	Kind string `json:"kind"`
+}
+
+// 获取路由信息，按匹配顺序排列（路径最长的在前），同一路径按HTTP方法排列
+func (r *Router) GetRouteInfos() []RouteInfo {
+	// 允许多个方法的路由在路由表中有多个键，只保留一个
+	routes := make([]*Route, 0, len(r.routes))
+	for _, route := range r.routes {
+		if !slices.Contains(routes, route) {
+			routes = append(routes, route)
+		}
+	}
+
+	infos := make([]RouteInfo, 0, len(routes))
+	for _, route := range routes {
+		methods := make([]string, len(route.methods))
+		for i, method := range route.methods {
+			methods[i] = string(method)
+		}
+		infos = append(infos, RouteInfo{
+			Host:       route.Host,
+			Path:       route.Path,
+			Method:     strings.Join(methods, ","),
+			Middleware: len(route.Middleware),
+			SecretKey:  len(route.SecretKey) > 0 || len(r.secretKey) > 0,
+			Admin:      route.Admin,
+			Kind:       getRouteKind(route),
+		})
+	}
+	sort.Slice(infos, func(i, j int) bool {
+		if len(infos[i].Path) != len(infos[j].Path) {
+			return len(infos[i].Path) > len(infos[j].Path)
+		}
+		if infos[i].Path != infos[j].Path {
+			return infos[i].Path < infos[j].Path
+		}
+		if infos[i].Host != infos[j].Host {
+			return infos[i].Host < infos[j].Host
+		}
+		return infos[i].Method < infos[j].Method
+	})
+	return infos
+}
+
+// 获取路由类型
+func getRouteKind(route *Route) string {
+	switch {
+	case route.Static != nil:
+		return "static"
+	case route.Webapp != nil:
+		return "webapp"
+	case route.Websocket:
+		return "websocket"
+	case route.Handler == nil:
+		return "group"
+	default:
+		return "handler"
+	}
+}
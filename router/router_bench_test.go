@@ -0,0 +1,126 @@
+package router
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/shi-yunsheng/gostar/router/handler"
+)
+
+// 基准测试的路由数量
+const benchRouteCount = 300
+
+// 基准测试的请求路径
+var benchPaths = []struct{ name, path string }{
+	{"static", "/api/resource150/list"},
+	{"param", "/api/resource150/42"},
+	{"optional", "/api/resource150/page"},
+	{"nested", "/api/resource150/42/comments/7"},
+	{"regex", "/raw/resource150/12345"},
+	{"notfound", "/api/missing/route"},
+}
+
+// 生成基准测试路由
+func benchRoutes() []Route {
+	noop := func(w *handler.Response, r handler.Request) any { return nil }
+	routes := make([]Route, 0, benchRouteCount*5)
+	for i := range benchRouteCount {
+		prefix := fmt.Sprintf("/api/resource%d", i)
+		routes = append(routes,
+			Route{Path: prefix + "/list", Handler: noop},
+			Route{Path: prefix + "/{id:int}", Handler: noop},
+			Route{Path: prefix + "/page/{page?:int:1}", Handler: noop},
+			Route{Path: prefix + "/{id:int}/comments/{cid:int}", Handler: noop},
+			Route{Path: fmt.Sprintf(`/raw/resource%d/\d+`, i), Handler: noop},
+		)
+	}
+	return routes
+}
+
+// 新建基准测试路由器
+func newBenchRouter() *Router {
+	r := NewRouter()
+	r.parseRoute(benchRoutes(), nil)
+	r.sortRoutes()
+	return r
+}
+
+// 原先的匹配方式：精确匹配失败后按路径长度逐个编译正则匹配
+type legacyMatcher struct {
+	routes       map[string]*Route
+	sortedRoutes []string
+}
+
+// 新建原先的匹配方式
+func newLegacyMatcher(r *Router) *legacyMatcher {
+	m := &legacyMatcher{routes: make(map[string]*Route)}
+	for key, route := range r.routes {
+		m.routes[key.Path] = route
+		m.sortedRoutes = append(m.sortedRoutes, key.Path)
+	}
+	sort.Slice(m.sortedRoutes, func(i, j int) bool {
+		return len(m.sortedRoutes[i]) > len(m.sortedRoutes[j])
+	})
+	return m
+}
+
+// 使用原先的方式匹配路由
+func (m *legacyMatcher) match(path string) (*Route, []handler.Param) {
+	route, ok := m.routes[path]
+	if !ok {
+		path = strings.TrimSuffix(path, "/")
+		for _, rt := range m.sortedRoutes {
+			re := regexp.MustCompile(m.routes[rt].Path)
+			if re.MatchString(path) {
+				route = m.routes[rt]
+				break
+			}
+		}
+	}
+	if route == nil {
+		return nil, nil
+	}
+
+	params := append([]handler.Param(nil), route.allParams...)
+	re := regexp.MustCompile(route.Path)
+	allMatches := re.FindAllStringSubmatch(path, -1)
+	if len(allMatches) == 0 {
+		return route, nil
+	}
+	for i, match := range allMatches[0][1:] {
+		if i >= len(params) {
+			break
+		}
+		if regexp.MustCompile(params[i].Pattern).MatchString(match) {
+			if value, err := convertParamValue(match, params[i].Type); err == nil {
+				params[i].Value = value
+			}
+		}
+	}
+	return route, params
+}
+
+func BenchmarkTreeMatch(b *testing.B) {
+	r := newBenchRouter()
+	for _, bp := range benchPaths {
+		b.Run(bp.name, func(b *testing.B) {
+			for b.Loop() {
+				r.match(string(GET), "", bp.path, "")
+			}
+		})
+	}
+}
+
+func BenchmarkLegacyMatch(b *testing.B) {
+	m := newLegacyMatcher(newBenchRouter())
+	for _, bp := range benchPaths {
+		b.Run(bp.name, func(b *testing.B) {
+			for b.Loop() {
+				m.match(bp.path)
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package router
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/shi-yunsheng/gostar/router/handler"
+)
+
+// 测试用的空处理器
+func noopHandler(w *handler.Response, r handler.Request) any {
+	return nil
+}
+
+// 新建测试路由器
+func newTestRouter(routes ...Route) *Router {
+	r := NewRouter()
+	r.UseRoute(routes)
+	return r
+}
+
+// 将路径参数转换为键值对
+func paramValues(params []handler.Param) map[string]any {
+	values := make(map[string]any, len(params))
+	for _, param := range params {
+		values[param.Key] = param.Value
+	}
+	return values
+}
+
+// 路由匹配测试用例
+type matchCase struct {
+	name   string
+	method string
+	host   string
+	path   string
+	// 期望匹配的路由模板，为空表示不匹配
+	want string
+	// 期望的参数值，为nil时不检查
+	params map[string]any
+	// 期望的允许方法，只在不匹配时检查
+	allow []string
+}
+
+// 执行路由匹配测试用例
+func runMatchCases(t *testing.T, r *Router, cases []matchCase) {
+	t.Helper()
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			method := tc.method
+			if method == "" {
+				method = string(GET)
+			}
+			route, params, allow := r.match(method, tc.host, tc.path, "")
+			if tc.want == "" {
+				if route != nil {
+					t.Fatalf("match %s %s: got route %s, want no match", method, tc.path, route.template)
+				}
+				if tc.allow != nil && !slices.Equal(allow, tc.allow) {
+					t.Fatalf("match %s %s: got allow %v, want %v", method, tc.path, allow, tc.allow)
+				}
+				return
+			}
+			if route == nil {
+				t.Fatalf("match %s %s: got no match, want %s", method, tc.path, tc.want)
+			}
+			if route.template != tc.want {
+				t.Fatalf("match %s %s: got route %s, want %s", method, tc.path, route.template, tc.want)
+			}
+			values := paramValues(params)
+			for key, want := range tc.params {
+				if got, ok := values[key]; !ok || got != want {
+					t.Errorf("match %s %s: param %s = %#v, want %#v", method, tc.path, key, got, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,232 @@
+package router
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/shi-yunsheng/gostar/router/handler"
+)
+
+// 静态文件和网站路由的文件路径参数名
+const filePathParam = "__filepath__"
+
+// 路由树节点，每一层对应路径中的一段
+// 匹配优先级：静态段 > 参数段（按正则长度从长到短） > 通配段
+type node struct {
+	// 静态子节点，键为路径段
+	static map[string]*node
+	// 参数子节点，路径段包含路径参数
+	params []*paramNode
+	// 在该节点结束的路由
+	leaves []*leaf
+	// 匹配剩余路径的路由（Static、Webapp和包含通配参数的路由）
+	catchAll []*catchAllLeaf
+}
+
+// 参数子节点
+type paramNode struct {
+	// 路径段正则表达式
+	pattern string
+	// 编译后的路径段正则表达式
+	re *regexp.Regexp
+	// 下一层节点
+	next *node
+}
+
+// 路由叶子
+type leaf struct {
+	route *Route
+	// 每个捕获值对应的参数下标，省略的可选参数不在其中
+	paramIndex []int
+}
+
+// 通配叶子
+type catchAllLeaf struct {
+	leaf
+	// 剩余路径不为空时，第一段是否不能为空，如：Static未开启AllowDir时不匹配目录本身
+	requireSegment bool
+	// 剩余路径是否不能为空，如：非可选的通配参数
+	required bool
+}
+
+// 新建路由树节点
+func newNode() *node {
+	return &node{static: make(map[string]*node)}
+}
+
+// 路径段，由模板路径拆分而来
+type segment struct {
+	// 静态段的文本，参数段的正则表达式
+	value string
+	// 是否为参数段
+	param bool
+	// 该段包含的参数下标
+	paramIndex []int
+	// 是否为可以整体省略的可选参数段，如：/{page?:int}
+	optional bool
+	// 是否为通配参数段，如：/{path*}
+	catchAll bool
+}
+
+// 判断模板路径是否包含正则表达式（路径参数之外），包含时只能使用正则匹配
+func isRegexTemplate(template string) bool {
+	depth := 0
+	for _, c := range template {
+		switch {
+		case c == '{':
+			depth++
+		case c == '}':
+			depth--
+		case depth == 0 && strings.ContainsRune(`\^$|?*+()[]`, c):
+			return true
+		}
+	}
+	return false
+}
+
+// 将模板路径拆分为路径段，params为模板路径解析出的参数
+func splitTemplate(template string, params []handler.Param) []segment {
+	segments := make([]segment, 0)
+	index := 0
+	for part := range strings.SplitSeq(template, "/") {
+		if part == "" {
+			continue
+		}
+		matches := findParams(part)
+		if len(matches) == 0 {
+			segments = append(segments, segment{value: part})
+			continue
+		}
+
+		seg := segment{param: true}
+		var pattern strings.Builder
+		last := 0
+		for _, match := range matches {
+			param := params[index]
+			if param.CatchAll {
+				segments = append(segments, segment{param: true, paramIndex: []int{index}, optional: param.Optional, catchAll: true})
+				return segments
+			}
+			pattern.WriteString(regexp.QuoteMeta(part[last:match[0]]))
+			pattern.WriteString(param.Pattern)
+			// 整段只有一个可选参数时，整段可以省略（包括前面的/），段内的可选参数只省略参数本身
+			if param.Optional {
+				if len(matches) == 1 && match[0] == 0 && match[1] == len(part) {
+					seg.optional = true
+				} else {
+					pattern.WriteString("?")
+				}
+			}
+			seg.paramIndex = append(seg.paramIndex, index)
+			last = match[1]
+			index++
+		}
+		pattern.WriteString(regexp.QuoteMeta(part[last:]))
+		seg.value = "^" + pattern.String() + "$"
+		segments = append(segments, seg)
+	}
+	return segments
+}
+
+// 添加路由，可选参数段会展开为包含和省略两种路径
+func (n *node) insert(segments []segment, route *Route, paramIndex []int, catchAll bool, requireSegment bool) {
+	if len(segments) == 0 {
+		l := leaf{route: route, paramIndex: paramIndex}
+		if catchAll {
+			n.catchAll = append(n.catchAll, &catchAllLeaf{leaf: l, requireSegment: requireSegment})
+		} else {
+			n.leaves = append(n.leaves, &l)
+		}
+		return
+	}
+
+	seg := segments[0]
+	// 通配参数段是最后一段，匹配剩余的全部路径
+	if seg.catchAll {
+		l := leaf{route: route, paramIndex: append(slices.Clip(paramIndex), seg.paramIndex...)}
+		n.catchAll = append(n.catchAll, &catchAllLeaf{leaf: l, required: !seg.optional})
+		return
+	}
+	if seg.optional {
+		n.insert(segments[1:], route, paramIndex, catchAll, requireSegment)
+	}
+	if !seg.param {
+		child, ok := n.static[seg.value]
+		if !ok {
+			child = newNode()
+			n.static[seg.value] = child
+		}
+		child.insert(segments[1:], route, paramIndex, catchAll, requireSegment)
+		return
+	}
+
+	var child *paramNode
+	for _, p := range n.params {
+		if p.pattern == seg.value {
+			child = p
+			break
+		}
+	}
+	if child == nil {
+		child = &paramNode{pattern: seg.value, re: regexp.MustCompile(seg.value), next: newNode()}
+		n.params = append(n.params, child)
+		// 正则越长越具体，优先匹配，与原先按路径长度排序的行为一致
+		slices.SortStableFunc(n.params, func(a, b *paramNode) int {
+			return len(b.pattern) - len(a.pattern)
+		})
+	}
+	child.next.insert(segments[1:], route, append(slices.Clip(paramIndex), seg.paramIndex...), catchAll, requireSegment)
+}
+
+// 按优先级依次访问与路径匹配的节点上的叶子（不同HTTP方法的路由）和捕获值，path为空或以/开头
+// visit返回true时停止匹配，否则继续尝试优先级更低的节点，返回是否已停止
+func (n *node) walk(path string, values []string, visit func([]*leaf, []string) bool) bool {
+	if path == "" {
+		if len(n.leaves) > 0 && visit(n.leaves, values) {
+			return true
+		}
+		if leaves := n.matchCatchAll(path); len(leaves) > 0 {
+			return visit(leaves, append(slices.Clip(values), ""))
+		}
+		return false
+	}
+
+	seg, rest, found := strings.Cut(path[1:], "/")
+	if found {
+		rest = "/" + rest
+	}
+
+	if child, ok := n.static[seg]; ok {
+		if child.walk(rest, values, visit) {
+			return true
+		}
+	}
+	for _, p := range n.params {
+		captures := p.re.FindStringSubmatch(seg)
+		if captures == nil {
+			continue
+		}
+		if p.next.walk(rest, append(slices.Clip(values), captures[1:]...), visit) {
+			return true
+		}
+	}
+	if leaves := n.matchCatchAll(path); len(leaves) > 0 {
+		return visit(leaves, append(slices.Clip(values), path))
+	}
+	return false
+}
+
+// 匹配剩余路径的路由，path为空或以/开头
+func (n *node) matchCatchAll(path string) []*leaf {
+	// 剩余路径不为空时，第一段是否为空
+	emptySegment := path != "" && (len(path) == 1 || path[1] == '/')
+	var leaves []*leaf
+	for _, c := range n.catchAll {
+		if c.requireSegment && emptySegment || c.required && strings.TrimPrefix(path, "/") == "" {
+			continue
+		}
+		leaves = append(leaves, &c.leaf)
+	}
+	return leaves
+}
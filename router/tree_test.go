@@ -0,0 +1,95 @@
+package router
+
+import (
+	"testing"
+
+	"github.com/shi-yunsheng/gostar/router/handler"
+)
+
+func TestTreeMatch(t *testing.T) {
+	r := newTestRouter(
+		Route{Path: "/users", Handler: noopHandler},
+		Route{Path: "/users/{id:int}", Handler: noopHandler},
+		Route{Path: "/users/{id:int}/posts/{pid:int}", Handler: noopHandler},
+		Route{Path: "/articles/{page?:int:1}", Handler: noopHandler},
+		Route{Path: "/reports/{day:date}", Handler: noopHandler},
+		Route{Path: "/prices/{value:float}", Handler: noopHandler},
+		Route{Path: "/flags/{on:bool}", Handler: noopHandler},
+		Route{Path: "/greet/{name:guest}", Handler: noopHandler},
+		Route{Path: "/v{version:int}/items", Handler: noopHandler},
+	)
+	day, err := convertDate("2024-03-05")
+	if err != nil {
+		t.Fatal(err)
+	}
+	runMatchCases(t, r, []matchCase{
+		{name: "static", path: "/users", want: "/users"},
+		{name: "trailing slash", path: "/users/", want: "/users"},
+		{name: "int", path: "/users/42", want: "/users/{id:int}", params: map[string]any{"id": int64(42)}},
+		{name: "negative int", path: "/users/-3", want: "/users/{id:int}", params: map[string]any{"id": int64(-3)}},
+		{name: "int miss", path: "/users/abc"},
+		{name: "nested", path: "/users/42/posts/7", want: "/users/{id:int}/posts/{pid:int}", params: map[string]any{"id": int64(42), "pid": int64(7)}},
+		{name: "nested miss", path: "/users/42/posts"},
+		{name: "optional default", path: "/articles", want: "/articles/{page?:int:1}", params: map[string]any{"page": int64(1)}},
+		{name: "optional value", path: "/articles/3", want: "/articles/{page?:int:1}", params: map[string]any{"page": int64(3)}},
+		{name: "optional miss", path: "/articles/x"},
+		{name: "date", path: "/reports/2024-03-05", want: "/reports/{day:date}", params: map[string]any{"day": day}},
+		{name: "date miss", path: "/reports/2024-13-05"},
+		{name: "float", path: "/prices/1.5", want: "/prices/{value:float}", params: map[string]any{"value": 1.5}},
+		{name: "float int", path: "/prices/2", want: "/prices/{value:float}", params: map[string]any{"value": 2.0}},
+		{name: "bool", path: "/flags/true", want: "/flags/{on:bool}", params: map[string]any{"on": true}},
+		{name: "bool miss", path: "/flags/yes"},
+		{name: "str default", path: "/greet/bob", want: "/greet/{name:guest}", params: map[string]any{"name": "bob"}},
+		{name: "segment prefix", path: "/v2/items", want: "/v{version:int}/items", params: map[string]any{"version": int64(2)}},
+		{name: "not found", path: "/missing"},
+		{name: "too long", path: "/users/42/extra"},
+	})
+}
+
+func TestRegexRouteMatch(t *testing.T) {
+	r := newTestRouter(
+		Route{Path: `/raw/\d+`, Handler: noopHandler},
+		Route{Path: `/raw/\d+/{name}`, Handler: noopHandler},
+		Route{Path: `/files/(.*)`, Handler: noopHandler},
+		Route{Path: "/raw/latest", Handler: noopHandler},
+	)
+	runMatchCases(t, r, []matchCase{
+		{name: "regex", path: "/raw/123", want: `/raw/\d+`},
+		{name: "regex longest first", path: "/raw/123/report", want: `/raw/\d+/{name}`, params: map[string]any{"name": "report"}},
+		{name: "tree before regex", path: "/raw/latest", want: "/raw/latest"},
+		{name: "regex wildcard", path: "/files/a/b.txt", want: `/files/(.*)`},
+		{name: "regex miss", path: "/raw/abc"},
+	})
+}
+
+func TestMatchPriority(t *testing.T) {
+	r := newTestRouter(
+		Route{Path: "/items/new", Handler: noopHandler},
+		Route{Path: "/items/{id:int}", Handler: noopHandler},
+		Route{Path: "/items/{name}", Handler: noopHandler},
+		Route{Path: "/items/{path*}", Handler: noopHandler},
+		Route{Path: "/docs/{path*?}", Handler: noopHandler},
+		Route{Path: "/docs/{section}/index", Handler: noopHandler},
+	)
+	runMatchCases(t, r, []matchCase{
+		{name: "static first", path: "/items/new", want: "/items/new"},
+		{name: "longer pattern first", path: "/items/12", want: "/items/{id:int}", params: map[string]any{"id": int64(12)}},
+		{name: "param before catch-all", path: "/items/box", want: "/items/{name}", params: map[string]any{"name": "box"}},
+		{name: "catch-all", path: "/items/a/b/c", want: "/items/{path*}", params: map[string]any{"path": "a/b/c"}},
+		{name: "required catch-all miss", path: "/items"},
+		{name: "backtrack to catch-all", path: "/docs/guide/intro", want: "/docs/{path*?}", params: map[string]any{"path": "guide/intro"}},
+		{name: "param over catch-all", path: "/docs/guide/index", want: "/docs/{section}/index", params: map[string]any{"section": "guide"}},
+		{name: "optional catch-all", path: "/docs", want: "/docs/{path*?}"},
+	})
+}
+
+func TestStaticFilePath(t *testing.T) {
+	r := newTestRouter(Route{Path: "/assets", Static: &handler.Static{Path: "."}})
+	route, params, _ := r.match(string(GET), "", "/assets/css/site.css", "")
+	if route == nil {
+		t.Fatal("static route not matched")
+	}
+	if got := paramValues(params)[filePathParam]; got != "/css/site.css" {
+		t.Fatalf("file path = %#v, want %q", got, "/css/site.css")
+	}
+}
@@ -0,0 +1,115 @@
+package router
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// 根据路由名称生成URL，params为路径参数，query为查询参数
+// 路径参数会按类型校验，省略的可选参数段不会出现在URL中，省略的必填参数使用默认值
+func (r *Router) URL(name string, params map[string]any, query url.Values) (string, error) {
+	route, ok := r.names[name]
+	if !ok {
+		return "", fmt.Errorf("route %s not found", name)
+	}
+	if isRegexTemplate(route.template) {
+		return "", fmt.Errorf("route %s contains regular expression, cannot build url", name)
+	}
+
+	_, allParams := r.parsePath(route.template)
+	used := make(map[string]bool, len(allParams))
+	index := 0
+	var path strings.Builder
+	for part := range strings.SplitSeq(route.template, "/") {
+		if part == "" {
+			continue
+		}
+		matches := findParams(part)
+		if len(matches) == 0 {
+			path.WriteString("/" + part)
+			continue
+		}
+
+		var segment strings.Builder
+		last := 0
+		for _, match := range matches {
+			param := allParams[index]
+			index++
+			used[param.Key] = true
+
+			value, ok := params[param.Key]
+			if !ok || value == nil {
+				if param.Optional {
+					value = ""
+				} else if param.Default != nil {
+					value = param.Default
+				} else {
+					return "", fmt.Errorf("route %s: missing path parameter %s", name, param.Key)
+				}
+			}
+			text := formatParamValue(value)
+			if text == "" && !param.Optional {
+				return "", fmt.Errorf("route %s: missing path parameter %s", name, param.Key)
+			}
+			if text != "" {
+				if !regexp.MustCompile("^" + param.Pattern + "$").MatchString(text) {
+					return "", fmt.Errorf("route %s: invalid path parameter %s, must be %s. Got: %s", name, param.Key, param.Type, text)
+				}
+				if _, err := convertParamValue(text, param.Type); err != nil {
+					return "", fmt.Errorf("route %s: invalid path parameter %s, must be %s. Got: %s. Error: %w", name, param.Key, param.Type, text, err)
+				}
+			}
+			segment.WriteString(part[last:match[0]])
+			// 通配参数的值可以包含/，逐段转义
+			if param.CatchAll {
+				values := strings.Split(text, "/")
+				for i, value := range values {
+					values[i] = url.PathEscape(value)
+				}
+				text = strings.Join(values, "/")
+			} else {
+				text = url.PathEscape(text)
+			}
+			segment.WriteString(text)
+			last = match[1]
+		}
+		segment.WriteString(part[last:])
+		// 省略的可选参数段整段省略
+		if segment.Len() == 0 {
+			continue
+		}
+		path.WriteString("/" + segment.String())
+	}
+	for key := range params {
+		if !used[key] {
+			return "", fmt.Errorf("route %s: unknown path parameter %s", name, key)
+		}
+	}
+
+	result := path.String()
+	if result == "" {
+		result = "/"
+	}
+	if len(query) > 0 {
+		result += "?" + query.Encode()
+	}
+	return result, nil
+}
+
+// 将路径参数值转换为字符串
+func formatParamValue(value any) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case time.Time:
+		if v.Hour() == 0 && v.Minute() == 0 && v.Second() == 0 {
+			return v.Format("2006-01-02")
+		}
+		return v.Format("2006-01-02 15:04:05")
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,78 @@
+package router
+
+import (
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestURL(t *testing.T) {
+	r := newTestRouter(
+		Route{Path: "/users", Name: "users", Children: []Route{
+			{Path: "/{id:int}", Name: "show", Handler: noopHandler},
+			{Path: "/{id:int}/posts/{page?:int:1}", Name: "posts", Handler: noopHandler},
+		}},
+		Route{Path: "/archive/{day:date}", Name: "archive", Handler: noopHandler},
+		Route{Path: "/list/{page:int:1}", Name: "list", Handler: noopHandler},
+		Route{Path: "/files/{path*}", Name: "files", Handler: noopHandler},
+		Route{Path: "/v{version:int}/status", Name: "status", Handler: noopHandler},
+		Route{Path: "/search/{kind:enum(all|users)}", Name: "search", Handler: noopHandler},
+		Route{Path: `/raw/\d+`, Name: "raw", Handler: noopHandler},
+		Route{Path: "/", Name: "home", Handler: noopHandler},
+	)
+	cases := []struct {
+		name   string
+		route  string
+		params map[string]any
+		query  url.Values
+		want   string
+		err    bool
+	}{
+		{name: "nested name", route: "users.show", params: map[string]any{"id": 1}, want: "/users/1"},
+		{name: "query", route: "users.show", params: map[string]any{"id": int64(2)}, query: url.Values{"tab": {"info"}}, want: "/users/2?tab=info"},
+		{name: "optional omitted", route: "users.posts", params: map[string]any{"id": 1}, want: "/users/1/posts"},
+		{name: "optional given", route: "users.posts", params: map[string]any{"id": 1, "page": 3}, want: "/users/1/posts/3"},
+		{name: "date", route: "archive", params: map[string]any{"day": time.Date(2024, 3, 5, 0, 0, 0, 0, time.Local)}, want: "/archive/2024-03-05"},
+		{name: "default", route: "list", want: "/list/1"},
+		{name: "catch-all", route: "files", params: map[string]any{"path": "a b/c.txt"}, want: "/files/a%20b/c.txt"},
+		{name: "segment prefix", route: "status", params: map[string]any{"version": 2}, want: "/v2/status"},
+		{name: "enum", route: "search", params: map[string]any{"kind": "users"}, want: "/search/users"},
+		{name: "root", route: "home", want: "/"},
+		{name: "invalid type", route: "users.show", params: map[string]any{"id": "abc"}, err: true},
+		{name: "int overflow", route: "users.show", params: map[string]any{"id": "99999999999999999999"}, err: true},
+		{name: "invalid enum", route: "search", params: map[string]any{"kind": "posts"}, err: true},
+		{name: "missing", route: "users.show", err: true},
+		{name: "unknown param", route: "users.show", params: map[string]any{"id": 1, "x": 2}, err: true},
+		{name: "regex route", route: "raw", err: true},
+		{name: "unknown route", route: "missing", err: true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := r.URL(tc.route, tc.params, tc.query)
+			if tc.err {
+				if err == nil {
+					t.Fatalf("URL(%s) = %q, want error", tc.route, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("URL(%s): %v", tc.route, err)
+			}
+			if got != tc.want {
+				t.Fatalf("URL(%s) = %q, want %q", tc.route, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestDuplicateRouteName(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected panic")
+		}
+	}()
+	newTestRouter(
+		Route{Path: "/a", Name: "same", Handler: noopHandler},
+		Route{Path: "/b", Name: "same", Handler: noopHandler},
+	)
+}
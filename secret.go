@@ -0,0 +1,230 @@
+package gostar
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// 加密配置值使用的环境变量
+const (
+	// 密钥，16、24或32字节（对应AES-128、AES-192、AES-256），可以是base64或hex编码
+	envSecretKey = "GOSTAR_SECRET_KEY"
+	// 密钥文件路径，文件内容格式同GOSTAR_SECRET_KEY
+	envSecretKeyFile = "GOSTAR_SECRET_KEY_FILE"
+)
+
+// 加密配置值的前缀和后缀，如：ENC(base64...)
+const (
+	encryptedPrefix = "ENC("
+	encryptedSuffix = ")"
+)
+
+var (
+	secretKeyLock sync.RWMutex
+	// 通过SetSecretKey设置的密钥，优先于环境变量
+	secretKey []byte
+)
+
+// 设置解密配置值使用的密钥，需要在创建实例前调用，设置后不再读取GOSTAR_SECRET_KEY和GOSTAR_SECRET_KEY_FILE
+func SetSecretKey(key []byte) {
+	secretKeyLock.Lock()
+	defer secretKeyLock.Unlock()
+	secretKey = append([]byte(nil), key...)
+}
+
+// 获取密钥，依次使用SetSecretKey设置的密钥、GOSTAR_SECRET_KEY和GOSTAR_SECRET_KEY_FILE
+func getSecretKey() ([]byte, error) {
+	secretKeyLock.RLock()
+	key := secretKey
+	secretKeyLock.RUnlock()
+	if key != nil {
+		return checkSecretKey(key)
+	}
+
+	if value := os.Getenv(envSecretKey); value != "" {
+		key, err := ParseSecretKey(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", envSecretKey, err)
+		}
+		return key, nil
+	}
+	if file := os.Getenv(envSecretKeyFile); file != "" {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("read secret key file %s failed: %w", file, err)
+		}
+		key, err := ParseSecretKey(string(data))
+		if err != nil {
+			return nil, fmt.Errorf("invalid secret key file %s: %w", file, err)
+		}
+		return key, nil
+	}
+
+	return nil, fmt.Errorf("secret key not found, please set %s or %s", envSecretKey, envSecretKeyFile)
+}
+
+// 解析密钥，支持base64、hex编码或16、24、32字节的原始字符串
+func ParseSecretKey(value string) ([]byte, error) {
+	value = strings.TrimSpace(value)
+	if key, err := base64.StdEncoding.DecodeString(value); err == nil && isValidKeySize(len(key)) {
+		return key, nil
+	}
+	if key, err := hex.DecodeString(value); err == nil && isValidKeySize(len(key)) {
+		return key, nil
+	}
+	return checkSecretKey([]byte(value))
+}
+
+// 校验密钥长度
+func checkSecretKey(key []byte) ([]byte, error) {
+	if !isValidKeySize(len(key)) {
+		return nil, fmt.Errorf("secret key must be 16, 24 or 32 bytes, got %d", len(key))
+	}
+	return key, nil
+}
+
+// 是否为AES支持的密钥长度
+func isValidKeySize(size int) bool {
+	return size == 16 || size == 24 || size == 32
+}
+
+// 生成32字节的随机密钥，返回base64编码
+func GenerateSecretKey() (string, error) {
+	key := make([]byte, 32)
+	if _, err := io.ReadFull(rand.Reader, key); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(key), nil
+}
+
+// 使用AES-GCM加密配置值，返回ENC(base64...)格式，可直接写入配置文件
+// 不指定key时使用SetSecretKey、GOSTAR_SECRET_KEY或GOSTAR_SECRET_KEY_FILE提供的密钥
+func EncryptSecret(plaintext string, key ...[]byte) (string, error) {
+	gcm, err := newSecretCipher(key...)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", err
+	}
+	sealed := gcm.Seal(nonce, nonce, []byte(plaintext), nil)
+	return encryptedPrefix + base64.StdEncoding.EncodeToString(sealed) + encryptedSuffix, nil
+}
+
+// 解密ENC(base64...)格式的配置值，不是加密值时原样返回
+// 不指定key时使用SetSecretKey、GOSTAR_SECRET_KEY或GOSTAR_SECRET_KEY_FILE提供的密钥
+func DecryptSecret(value string, key ...[]byte) (string, error) {
+	if !IsEncryptedSecret(value) {
+		return value, nil
+	}
+	gcm, err := newSecretCipher(key...)
+	if err != nil {
+		return "", err
+	}
+	return decryptWithCipher(gcm, value)
+}
+
+// 使用指定的加密器解密配置值
+func decryptWithCipher(gcm cipher.AEAD, value string) (string, error) {
+	sealed, err := base64.StdEncoding.DecodeString(strings.TrimSuffix(strings.TrimPrefix(value, encryptedPrefix), encryptedSuffix))
+	if err != nil {
+		return "", fmt.Errorf("invalid encrypted value: %w", err)
+	}
+	if len(sealed) < gcm.NonceSize() {
+		return "", errors.New("invalid encrypted value: too short")
+	}
+	plaintext, err := gcm.Open(nil, sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():], nil)
+	if err != nil {
+		return "", errors.New("decrypt failed, the secret key may be wrong")
+	}
+	return string(plaintext), nil
+}
+
+// 判断是否为ENC(...)格式的加密值
+func IsEncryptedSecret(value string) bool {
+	return strings.HasPrefix(value, encryptedPrefix) && strings.HasSuffix(value, encryptedSuffix)
+}
+
+// 创建AES-GCM加密器
+func newSecretCipher(key ...[]byte) (cipher.AEAD, error) {
+	var secret []byte
+	var err error
+	if len(key) > 0 {
+		secret, err = checkSecretKey(key[0])
+	} else {
+		secret, err = getSecretKey()
+	}
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(secret)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// 解密配置中所有ENC(...)格式的字符串，包括数据库、Redis和自定义配置
+// 只有存在加密值时才读取密钥，解密失败的值会保留原样并返回错误
+func decryptConfig(allConfig map[string]any, sources []configSource) ConfigErrors {
+	v := &configValidator{sources: sources}
+	var gcm cipher.AEAD
+	var keyErr error
+
+	var walk func(value any, path string) any
+	walk = func(value any, path string) any {
+		switch value := value.(type) {
+		case map[string]any:
+			// 按键排序，保证错误顺序稳定
+			for _, key := range slices.Sorted(maps.Keys(value)) {
+				value[key] = walk(value[key], joinConfigPath(path, key))
+			}
+		case []any:
+			for i, item := range value {
+				value[i] = walk(item, joinConfigPath(path, strconv.Itoa(i)))
+			}
+		case string:
+			if !IsEncryptedSecret(value) {
+				return value
+			}
+			if gcm == nil && keyErr == nil {
+				gcm, keyErr = newSecretCipher()
+			}
+			if keyErr != nil {
+				v.add(path, "cannot decrypt value: %v", keyErr)
+				return value
+			}
+			plaintext, err := decryptWithCipher(gcm, value)
+			if err != nil {
+				v.add(path, "cannot decrypt value: %v", err)
+				return value
+			}
+			return plaintext
+		}
+		return value
+	}
+	walk(allConfig, "")
+
+	return v.errs
+}
+
+// 拼接配置路径
+func joinConfigPath(prefix string, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
@@ -0,0 +1,81 @@
+package gostar
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/shi-yunsheng/gostar/date"
+	"github.com/shi-yunsheng/gostar/utils"
+)
+
+// 服务器默认限制
+const (
+	defaultReadHeaderTimeout = 10 * time.Second
+	defaultIdleTimeout       = 120 * time.Second
+	defaultMaxHeaderBytes    = http.DefaultMaxHeaderBytes
+	defaultMaxBodySize       = 32 << 20
+)
+
+// HTTP服务器配置
+type serverConfig struct {
+	// 读取整个请求（包括请求体）的超时时间，默认不限制
+	ReadTimeout string `yaml:"read_timeout"`
+	// 读取请求头的超时时间，默认10s，用于防止慢速攻击
+	ReadHeaderTimeout string `yaml:"read_header_timeout"`
+	// 写入响应的超时时间，默认不限制
+	WriteTimeout string `yaml:"write_timeout"`
+	// 长连接空闲超时时间，默认120s
+	IdleTimeout string `yaml:"idle_timeout"`
+	// 请求头最大大小，默认1MB
+	MaxHeaderBytes string `yaml:"max_header_bytes"`
+	// 请求体最大大小，默认32MB，设置为None时不限制，超出时返回413
+	MaxBodySize string `yaml:"max_body_size"`
+	// 是否禁用长连接
+	DisableKeepAlive bool `yaml:"disable_keep_alive"`
+}
+
+// 解析时长，为空或不合法时使用默认值
+func parseServerDuration(value string, defaultVal time.Duration) time.Duration {
+	if strings.TrimSpace(value) == "" {
+		return defaultVal
+	}
+	duration, err := date.ParseTimeDuration(value)
+	if err != nil {
+		return defaultVal
+	}
+	return duration
+}
+
+// 解析大小，为空或不合法时使用默认值，None表示不限制
+func parseServerSize(value string, defaultVal int64) int64 {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return defaultVal
+	}
+	if value == "None" {
+		return 0
+	}
+	size, err := utils.ParseSize(value)
+	if err != nil {
+		return defaultVal
+	}
+	return size
+}
+
+// 将服务器配置应用到HTTP服务器
+func (c *serverConfig) apply(server *http.Server) {
+	server.ReadTimeout = parseServerDuration(c.ReadTimeout, 0)
+	server.ReadHeaderTimeout = parseServerDuration(c.ReadHeaderTimeout, defaultReadHeaderTimeout)
+	server.WriteTimeout = parseServerDuration(c.WriteTimeout, 0)
+	server.IdleTimeout = parseServerDuration(c.IdleTimeout, defaultIdleTimeout)
+	server.MaxHeaderBytes = int(parseServerSize(c.MaxHeaderBytes, defaultMaxHeaderBytes))
+	if c.DisableKeepAlive {
+		server.SetKeepAlivesEnabled(false)
+	}
+}
+
+// 获取请求体最大大小，0表示不限制
+func (c *serverConfig) maxBodySize() int64 {
+	return parseServerSize(c.MaxBodySize, defaultMaxBodySize)
+}
@@ -0,0 +1,24 @@
+package gostar
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRedirectServerLimits(t *testing.T) {
+	g := newTestApp(t, "server:\n  read_timeout: 3s\n  read_header_timeout: 2s\n  idle_timeout: 4s\n  max_header_bytes: 4KB\ntls:\n  redirect_bind: 127.0.0.1:0\n")
+	server := g.newRedirectServer()
+
+	if server.ReadTimeout != 3*time.Second {
+		t.Errorf("read timeout = %v, want 3s", server.ReadTimeout)
+	}
+	if server.ReadHeaderTimeout != 2*time.Second {
+		t.Errorf("read header timeout = %v, want 2s", server.ReadHeaderTimeout)
+	}
+	if server.IdleTimeout != 4*time.Second {
+		t.Errorf("idle timeout = %v, want 4s", server.IdleTimeout)
+	}
+	if server.MaxHeaderBytes != 4096 {
+		t.Errorf("max header bytes = %d, want 4096", server.MaxHeaderBytes)
+	}
+}
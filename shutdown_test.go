@@ -0,0 +1,89 @@
+package gostar
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shi-yunsheng/gostar/router"
+	"github.com/shi-yunsheng/gostar/router/handler"
+)
+
+// 新建测试用的独立实例
+func newTestApp(t *testing.T, config string) *App {
+	t.Helper()
+	g, err := NewIsolatedFromReader(strings.NewReader("bind: 127.0.0.1:0\n"+config), "config.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { g.Close() })
+	return g
+}
+
+func TestShutdownDelay(t *testing.T) {
+	cases := []struct {
+		name   string
+		config string
+		want   time.Duration
+	}{
+		{name: "health disabled", want: 0},
+		{name: "health enabled", config: "health:\n  enable: true\n", want: defaultShutdownDelay},
+		{name: "configured", config: "health:\n  enable: true\nshutdown_delay: 2s\n", want: 2 * time.Second},
+		{name: "disabled", config: "health:\n  enable: true\nshutdown_delay: 0s\n", want: 0},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := newTestApp(t, tc.config).getShutdownDelay(); got != tc.want {
+				t.Fatalf("shutdown delay = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestShutdownDrain(t *testing.T) {
+	const delay = 300 * time.Millisecond
+	app := newTestApp(t, "health:\n  enable: true\nshutdown_delay: 300ms\n")
+	app.UseRouter([]router.Route{{Path: "/ping", Handler: func(w *handler.Response, r handler.Request) any {
+		return "pong"
+	}}})
+	h := app.Handler()
+
+	get := func(path string) int {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		return w.Code
+	}
+	if code := get("/readyz"); code != http.StatusOK {
+		t.Fatalf("readyz before shutdown = %d, want 200", code)
+	}
+
+	start := time.Now()
+	done := make(chan error, 1)
+	go func() { done <- app.Shutdown() }()
+
+	// 等待期间就绪检查返回503，请求仍正常处理
+	deadline := time.Now().Add(delay / 2)
+	for get("/readyz") != http.StatusServiceUnavailable {
+		if time.Now().After(deadline) {
+			t.Fatal("readyz did not fail during shutdown delay")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if code := get("/ping"); code != http.StatusOK {
+		t.Fatalf("ping during shutdown delay = %d, want 200", code)
+	}
+	select {
+	case <-done:
+		t.Fatal("shutdown finished before the delay")
+	default:
+	}
+
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed < delay {
+		t.Fatalf("shutdown took %v, want at least %v", elapsed, delay)
+	}
+}
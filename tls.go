@@ -0,0 +1,157 @@
+package gostar
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// TLS配置
+type tlsConfig struct {
+	// 是否启用HTTPS
+	Enable bool `yaml:"enable"`
+	// 证书文件路径
+	CertFile string `yaml:"cert_file"`
+	// 私钥文件路径
+	KeyFile string `yaml:"key_file"`
+	// 最低TLS版本，支持：1.0, 1.1, 1.2, 1.3，默认1.2
+	MinVersion string `yaml:"min_version"`
+	// 允许的加密套件名称，如：TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256，为空则使用Go默认值
+	CipherSuites []string `yaml:"cipher_suites"`
+	// 客户端CA证书文件路径，设置后开启双向认证（mTLS）
+	ClientCAFile string `yaml:"client_ca_file"`
+	// 客户端证书校验方式，支持：require（必须提供证书），optional（提供时校验），默认require
+	ClientAuth string `yaml:"client_auth"`
+	// HTTP重定向监听地址，设置后该地址上的HTTP请求将被重定向到HTTPS，如：0.0.0.0:80
+	RedirectBind string `yaml:"redirect_bind"`
+}
+
+// HTTP重定向监听地址类型
+const redirectListener = "redirect"
+
+// TLS版本映射
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// 根据名称获取加密套件ID
+func getCipherSuiteID(name string) (uint16, bool) {
+	for _, suite := range tls.CipherSuites() {
+		if suite.Name == name {
+			return suite.ID, true
+		}
+	}
+	for _, suite := range tls.InsecureCipherSuites() {
+		if suite.Name == name {
+			return suite.ID, true
+		}
+	}
+	return 0, false
+}
+
+// 构建TLS配置
+func (c *tlsConfig) build() (*tls.Config, error) {
+	if strings.TrimSpace(c.CertFile) == "" || strings.TrimSpace(c.KeyFile) == "" {
+		return nil, errors.New("tls cert_file and key_file are required when tls is enabled")
+	}
+
+	conf := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+	}
+
+	if strings.TrimSpace(c.MinVersion) != "" {
+		version, ok := tlsVersions[strings.TrimSpace(c.MinVersion)]
+		if !ok {
+			return nil, fmt.Errorf("unsupported tls min_version: %s, must be 1.0, 1.1, 1.2 or 1.3", c.MinVersion)
+		}
+		conf.MinVersion = version
+	}
+
+	for _, name := range c.CipherSuites {
+		id, ok := getCipherSuiteID(strings.TrimSpace(name))
+		if !ok {
+			return nil, fmt.Errorf("unsupported tls cipher suite: %s", name)
+		}
+		conf.CipherSuites = append(conf.CipherSuites, id)
+	}
+	// 配置双向认证
+	if strings.TrimSpace(c.ClientCAFile) != "" {
+		caData, err := os.ReadFile(c.ClientCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("read tls client_ca_file failed: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caData) {
+			return nil, fmt.Errorf("no valid certificate found in tls client_ca_file: %s", c.ClientCAFile)
+		}
+		conf.ClientCAs = pool
+
+		switch strings.TrimSpace(c.ClientAuth) {
+		case "", "require":
+			conf.ClientAuth = tls.RequireAndVerifyClientCert
+		case "optional":
+			conf.ClientAuth = tls.VerifyClientCertIfGiven
+		default:
+			return nil, fmt.Errorf("unsupported tls client_auth: %s, must be require or optional", c.ClientAuth)
+		}
+	}
+
+	return conf, nil
+}
+
+// 创建HTTP重定向到HTTPS的服务器
+func (g *goStar) newRedirectServer() *http.Server {
+	config := g.config.Load()
+	_, httpsPort, _ := net.SplitHostPort(config.Bind.firstTCP())
+
+	server := &http.Server{
+		Addr: config.TLS.RedirectBind,
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			host := r.Host
+			if h, _, err := net.SplitHostPort(host); err == nil {
+				host = h
+			}
+			if httpsPort != "" && httpsPort != "443" {
+				host = net.JoinHostPort(host, httpsPort)
+			}
+
+			http.Redirect(w, r, "https://"+host+r.URL.RequestURI(), http.StatusMovedPermanently)
+		}),
+	}
+	// 与主服务器使用相同的超时和请求头大小限制
+	config.Server.apply(server)
+	return server
+}
+
+// 启动HTTP重定向服务器
+func (g *goStar) startRedirectServer() error {
+	redirectBind := g.config.Load().TLS.RedirectBind
+	if strings.TrimSpace(redirectBind) == "" {
+		return nil
+	}
+
+	listeners, err := g.listenAll(redirectListener, []string{redirectBind})
+	if err != nil {
+		return err
+	}
+
+	g.redirectServer = g.newRedirectServer()
+	g.logger.I("GoStar redirects HTTP on " + redirectBind + " to HTTPS")
+
+	for _, ln := range listeners {
+		go func(ln net.Listener) {
+			if err := g.redirectServer.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				g.logger.E("HTTP redirect server failed: %v", err)
+			}
+		}(ln)
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+package utils
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// 雪花算法相关常量
+const (
+	// 时间戳占用位数
+	timestampBits = 41
+	// 机器ID占用位数
+	machineIDBits = 10
+	// 序列号占用位数
+	sequenceBits = 12
+	// 最大值
+	maxMachineID = -1 ^ (-1 << machineIDBits)
+	maxSequence  = -1 ^ (-1 << sequenceBits)
+	maxTimestamp = -1 ^ (-1 << timestampBits)
+	// 位移
+	machineIDShift = sequenceBits
+	timestampShift = sequenceBits + machineIDBits
+	// 起始时间戳 (2020-01-01 00:00:00 UTC)
+	epoch = 1577836800000
+)
+
+// 雪花算法生成器
+type SnowflakeGenerator struct {
+	mutex     sync.Mutex
+	machineID int64
+	sequence  int64
+	lastTime  int64
+}
+
+var (
+	snowflakeGen  *SnowflakeGenerator
+	snowflakeOnce sync.Once
+)
+
+// 获取雪花算法生成器实例
+func getSnowflakeGenerator() *SnowflakeGenerator {
+	snowflakeOnce.Do(func() {
+		// 使用机器ID的哈希值作为机器ID，确保分布式环境下的唯一性
+		machineID := int64(StringHash("snowflake") & maxMachineID)
+		snowflakeGen = &SnowflakeGenerator{
+			machineID: machineID,
+		}
+	})
+	return snowflakeGen
+}
+
+// 生成雪花ID
+func GenerateSnowflakeID(prefix ...string) (string, error) {
+	gen := getSnowflakeGenerator()
+	gen.mutex.Lock()
+	defer gen.mutex.Unlock()
+
+	now := time.Now().UnixMilli()
+	// 如果当前时间小于上次生成ID的时间，说明系统时钟回退
+	if now < gen.lastTime {
+		return "", fmt.Errorf("system clock rolled back, cannot generate ID")
+	}
+	// 检查时间戳是否超出范围
+	timeDiff := now - epoch
+	if timeDiff < 0 {
+		return "", fmt.Errorf("timestamp cannot be less than start time")
+	}
+	if timeDiff > maxTimestamp {
+		return "", fmt.Errorf("timestamp out of range, snowflake algorithm has expired")
+	}
+	// 如果是同一毫秒内，序列号递增
+	if now == gen.lastTime {
+		gen.sequence = (gen.sequence + 1) & maxSequence
+		// 序列号溢出，等待下一毫秒
+		if gen.sequence == 0 {
+			for now <= gen.lastTime {
+				now = time.Now().UnixMilli()
+			}
+		}
+	} else {
+		// 新的毫秒，序列号重置
+		gen.sequence = 0
+	}
+
+	gen.lastTime = now
+	// 生成ID
+	id := ((now - epoch) << timestampShift) | (gen.machineID << machineIDShift) | gen.sequence
+	// 添加前缀
+	if len(prefix) > 0 && prefix[0] != "" {
+		return fmt.Sprintf("%s%d", prefix[0], id), nil
+	}
+	return fmt.Sprintf("%d", id), nil
+}
+
+// 雪花ID，由ParseSnowflakeID解析
+type SnowflakeID struct {
+	// 原始ID
+	ID int64
+	// 生成时间
+	Time time.Time
+	// 机器ID
+	MachineID int64
+	// 序列号
+	Sequence int64
+}
+
+// 返回雪花ID的字符串形式，与GenerateSnowflakeID生成的ID相同（不含前缀）
+func (s SnowflakeID) String() string {
+	return strconv.FormatInt(s.ID, 10)
+}
+
+// 解析GenerateSnowflakeID生成的雪花ID（不含前缀），返回生成时间、机器ID和序列号
+func ParseSnowflakeID(id string) (SnowflakeID, error) {
+	value, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return SnowflakeID{}, err
+	}
+	if value < 0 {
+		return SnowflakeID{}, fmt.Errorf("snowflake id cannot be negative")
+	}
+	return SnowflakeID{
+		ID:        value,
+		Time:      time.UnixMilli(value>>timestampShift + epoch),
+		MachineID: value >> machineIDShift & maxMachineID,
+		Sequence:  value & maxSequence,
+	}, nil
+}
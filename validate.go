@@ -0,0 +1,454 @@
+package gostar
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"net"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shi-yunsheng/gostar/date"
+	"github.com/shi-yunsheng/gostar/model"
+	"github.com/shi-yunsheng/gostar/utils"
+
+	"gopkg.in/yaml.v3"
+)
+
+// 配置错误，包含配置路径和所在文件行号
+type ConfigError struct {
+	// 配置文件，通过环境变量覆盖的配置为空
+	File string
+	// 行号，无法定位时为0
+	Line int
+	// 配置路径，如：database.default.driver
+	Path string
+	// 错误信息
+	Message string
+}
+
+// 错误信息，如：config.yaml:12 database.default.driver: unsupported database driver "foo"
+func (e ConfigError) Error() string {
+	location := e.File
+	if location != "" && e.Line > 0 {
+		location = location + ":" + strconv.Itoa(e.Line)
+	}
+
+	switch {
+	case location != "" && e.Path != "":
+		return location + " " + e.Path + ": " + e.Message
+	case location != "":
+		return location + ": " + e.Message
+	case e.Path != "":
+		return e.Path + ": " + e.Message
+	default:
+		return e.Message
+	}
+}
+
+// 配置错误列表
+type ConfigErrors []ConfigError
+
+// 错误信息，每行一个错误
+func (e ConfigErrors) Error() string {
+	lines := make([]string, len(e))
+	for i, err := range e {
+		lines[i] = "  - " + err.Error()
+	}
+	return fmt.Sprintf("invalid config, %d error(s) found:\n%s", len(e), strings.Join(lines, "\n"))
+}
+
+// 配置校验器，收集所有错误
+type configValidator struct {
+	sources []configSource
+	errs    ConfigErrors
+}
+
+// 添加错误，并根据配置路径定位文件和行号，同一路径只保留第一个错误
+func (v *configValidator) add(path string, format string, args ...any) {
+	for _, err := range v.errs {
+		if err.Path == path {
+			return
+		}
+	}
+
+	file, line := locateConfigPath(v.sources, path)
+	v.errs = append(v.errs, ConfigError{
+		File:    file,
+		Line:    line,
+		Path:    path,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+// 判断路径或其下级路径是否已有错误
+func (v *configValidator) hasErrorUnder(path string) bool {
+	for _, err := range v.errs {
+		if err.Path == path || strings.HasPrefix(err.Path, path+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// 校验时长
+func (v *configValidator) duration(path string, value string) {
+	if strings.TrimSpace(value) == "" {
+		return
+	}
+	if _, err := date.ParseTimeDuration(value); err != nil {
+		v.add(path, "invalid duration %q: %v", value, err)
+	}
+}
+
+// 校验大小
+func (v *configValidator) size(path string, value string) {
+	if strings.TrimSpace(value) == "" || strings.TrimSpace(value) == "None" {
+		return
+	}
+	if _, err := utils.ParseSize(value); err != nil {
+		v.add(path, "invalid size %q: %v", value, err)
+	}
+}
+
+// 校验端口，allowZero为true时允许端口为0（表示使用默认端口）
+func (v *configValidator) port(path string, port int, allowZero bool) {
+	if port == 0 && allowZero {
+		return
+	}
+	if port < 1 || port > 65535 {
+		v.add(path, "invalid port %d, must be between 1 and 65535", port)
+	}
+}
+
+// 校验请求路径
+func (v *configValidator) urlPath(path string, value string) {
+	if value != "" && !strings.HasPrefix(value, "/") {
+		v.add(path, "invalid path %q, must start with /", value)
+	}
+}
+
+// 校验文件是否存在
+func (v *configValidator) file(path string, name string) {
+	if strings.TrimSpace(name) == "" {
+		v.add(path, "file path is required")
+		return
+	}
+	if !utils.IsFile(name) {
+		v.add(path, "file %q does not exist", name)
+	}
+}
+
+// 校验整个框架配置，返回包含所有错误的ConfigErrors
+func (c *config) validate() error {
+	v := &configValidator{sources: c.sources}
+	v.errs = append(v.errs, c.decodeErrs...)
+
+	if len(c.Bind) == 0 && !v.hasErrorUnder("bind") {
+		v.add("bind", "bind address is required, e.g.: 0.0.0.0:8000")
+	}
+	for i, bind := range c.Bind {
+		v.validateBind(bindPath("bind", len(c.Bind), i), bind)
+	}
+	for i, bind := range c.AdminBind {
+		v.validateBind(bindPath("admin_bind", len(c.AdminBind), i), bind)
+	}
+	if strings.TrimSpace(c.UnixSocket.Mode) != "" {
+		if _, err := parseFileMode(c.UnixSocket.Mode); err != nil {
+			v.add("unix_socket.mode", "%v", err)
+		}
+	}
+	v.duration("shutdown_timeout", c.ShutdownTimeout)
+	v.duration("shutdown_delay", c.ShutdownDelay)
+	// 服务器配置
+	v.duration("server.read_timeout", c.Server.ReadTimeout)
+	v.duration("server.read_header_timeout", c.Server.ReadHeaderTimeout)
+	v.duration("server.write_timeout", c.Server.WriteTimeout)
+	v.duration("server.idle_timeout", c.Server.IdleTimeout)
+	v.size("server.max_header_bytes", c.Server.MaxHeaderBytes)
+	v.size("server.max_body_size", c.Server.MaxBodySize)
+	v.duration("restart.ready_timeout", c.Restart.ReadyTimeout)
+	// 健康检查配置
+	v.duration("health.timeout", c.Health.Timeout)
+	v.urlPath("health.liveness_path", c.Health.LivenessPath)
+	v.urlPath("health.readiness_path", c.Health.ReadinessPath)
+	if strings.TrimSpace(c.Timezone) != "" {
+		if _, err := time.LoadLocation(c.Timezone); err != nil {
+			v.add("timezone", "invalid timezone %q: %v", c.Timezone, err)
+		}
+	}
+	// 日志配置
+	v.size("log.max_file_size", c.Log.MaxFileSize)
+	v.duration("log.max_save_days", c.Log.MaxSaveDays)
+	// TLS配置
+	if c.TLS.Enable {
+		v.validateTLS("tls", c.TLS)
+	}
+	// 数据库配置
+	for _, name := range slices.Sorted(maps.Keys(c.Database)) {
+		v.validateDatabase("database."+name, c.Database[name])
+	}
+	// Redis配置
+	for _, name := range slices.Sorted(maps.Keys(c.Redis)) {
+		v.validateRedis("redis."+name, c.Redis[name])
+	}
+
+	if len(v.errs) > 0 {
+		return v.errs
+	}
+	return nil
+}
+
+// 获取绑定地址的配置路径，只有一个地址时不带下标
+func bindPath(path string, count int, index int) string {
+	if count <= 1 {
+		return path
+	}
+	return path + "." + strconv.Itoa(index)
+}
+
+// 校验绑定地址
+func (v *configValidator) validateBind(path string, bind string) {
+	if strings.TrimSpace(bind) == "" {
+		v.add(path, "bind address is required, e.g.: 0.0.0.0:8000")
+		return
+	}
+
+	switch getBindNetwork(bind) {
+	case "unix":
+		if strings.TrimSpace(strings.TrimPrefix(bind, "unix:")) == "" {
+			v.add(path, "invalid bind address %q, unix socket path is required, e.g.: unix:/run/app.sock", bind)
+		}
+		return
+	case "fd":
+		if fd, err := strconv.Atoi(strings.TrimPrefix(bind, "fd:")); err != nil || fd < 0 {
+			v.add(path, "invalid bind address %q, must be fd:<number>, e.g.: fd:3", bind)
+		}
+		return
+	case "systemd":
+		return
+	}
+
+	_, port, err := net.SplitHostPort(bind)
+	if err != nil {
+		v.add(path, "invalid bind address %q, must be host:port, unix:/path, fd:<number> or systemd, e.g.: 0.0.0.0:8000", bind)
+		return
+	}
+	portNum, err := strconv.Atoi(port)
+	if err != nil || portNum < 0 || portNum > 65535 {
+		v.add(path, "invalid bind port %q, must be between 0 and 65535", port)
+	}
+}
+
+// 校验TLS配置
+func (v *configValidator) validateTLS(path string, c tlsConfig) {
+	v.file(path+".cert_file", c.CertFile)
+	v.file(path+".key_file", c.KeyFile)
+
+	if strings.TrimSpace(c.MinVersion) != "" {
+		if _, ok := tlsVersions[strings.TrimSpace(c.MinVersion)]; !ok {
+			v.add(path+".min_version", "unsupported tls version %q, must be 1.0, 1.1, 1.2 or 1.3", c.MinVersion)
+		}
+	}
+	for i, name := range c.CipherSuites {
+		if _, ok := getCipherSuiteID(strings.TrimSpace(name)); !ok {
+			v.add(fmt.Sprintf("%s.cipher_suites.%d", path, i), "unsupported cipher suite %q", name)
+		}
+	}
+	if strings.TrimSpace(c.ClientCAFile) != "" {
+		v.file(path+".client_ca_file", c.ClientCAFile)
+		switch strings.TrimSpace(c.ClientAuth) {
+		case "", "require", "optional":
+		default:
+			v.add(path+".client_auth", "unsupported client auth %q, must be require or optional", c.ClientAuth)
+		}
+	}
+	if strings.TrimSpace(c.RedirectBind) != "" {
+		v.validateBind(path+".redirect_bind", c.RedirectBind)
+	}
+}
+
+// 校验数据库配置
+func (v *configValidator) validateDatabase(path string, c model.DBConfig) {
+	if !model.IsSupportedDriver(c.Driver) {
+		v.add(path+".driver", "unsupported database driver %q, must be one of %s", c.Driver, strings.Join(model.SupportedDrivers(), ", "))
+		return
+	}
+	// 设置了DSN时，其他连接配置无效
+	if strings.TrimSpace(c.DSN) != "" {
+		return
+	}
+
+	if c.Driver == "sqlite" {
+		if strings.TrimSpace(c.Database) == "" {
+			v.add(path+".database", "database is required for sqlite")
+		}
+		return
+	}
+	if strings.TrimSpace(c.Host) == "" {
+		v.add(path+".host", "host is required when dsn is not set")
+	}
+	v.port(path+".port", c.Port, false)
+}
+
+// 校验Redis配置
+func (v *configValidator) validateRedis(path string, c model.RedisConfig) {
+	if strings.TrimSpace(c.DSN) != "" {
+		u, err := url.Parse(c.DSN)
+		if err != nil || (u.Scheme != "redis" && u.Scheme != "rediss" && u.Scheme != "unix") {
+			v.add(path+".dsn", "invalid redis dsn, must be like redis://:password@localhost:6379/0")
+		}
+		return
+	}
+
+	if strings.TrimSpace(c.Host) == "" {
+		v.add(path+".host", "host is required when dsn is not set")
+	}
+	v.port(path+".port", c.Port, false)
+	if c.DB < 0 {
+		v.add(path+".db", "invalid redis db %d, must not be negative", c.DB)
+	}
+}
+
+// 根据配置路径定位文件和行号，优先使用后加载的文件（环境配置覆盖基础配置）
+// 找不到完整路径时，返回最近的上级配置所在行
+func locateConfigPath(sources []configSource, path string) (string, int) {
+	segments := strings.Split(path, ".")
+	bestFile, bestLine, bestDepth := "", 0, 0
+
+	for i := len(sources) - 1; i >= 0; i-- {
+		line, depth := findConfigLine(sources[i].node, segments)
+		if depth == len(segments) {
+			return sources[i].name, line
+		}
+		if depth > bestDepth {
+			bestFile, bestLine, bestDepth = sources[i].name, line, depth
+		}
+	}
+
+	return bestFile, bestLine
+}
+
+// 在YAML节点中查找路径，返回匹配到的最深节点的行号和匹配深度
+func findConfigLine(node *yaml.Node, segments []string) (int, int) {
+	if node == nil {
+		return 0, 0
+	}
+	if node.Kind == yaml.DocumentNode && len(node.Content) > 0 {
+		node = node.Content[0]
+	}
+
+	line, depth := 0, 0
+	for _, segment := range segments {
+		var next *yaml.Node
+		switch node.Kind {
+		case yaml.MappingNode:
+			for i := 0; i+1 < len(node.Content); i += 2 {
+				if node.Content[i].Value == segment {
+					line = node.Content[i].Line
+					next = node.Content[i+1]
+					break
+				}
+			}
+		case yaml.SequenceNode:
+			if index, err := strconv.Atoi(segment); err == nil && index >= 0 && index < len(node.Content) {
+				next = node.Content[index]
+				line = next.Line
+			}
+		}
+		if next == nil {
+			break
+		}
+		node = next
+		depth++
+	}
+
+	return line, depth
+}
+
+// 在YAML节点中根据行号查找配置路径
+func findConfigPathByLine(node *yaml.Node, line int, prefix string) string {
+	if node == nil {
+		return ""
+	}
+	switch node.Kind {
+	case yaml.DocumentNode:
+		for _, child := range node.Content {
+			if path := findConfigPathByLine(child, line, prefix); path != "" {
+				return path
+			}
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			path := key.Value
+			if prefix != "" {
+				path = prefix + "." + key.Value
+			}
+			if key.Line == line || (value.Kind == yaml.ScalarNode && value.Line == line) {
+				return path
+			}
+			if found := findConfigPathByLine(value, line, path); found != "" {
+				return found
+			}
+		}
+	case yaml.SequenceNode:
+		for i, child := range node.Content {
+			path := prefix + "." + strconv.Itoa(i)
+			if child.Kind == yaml.ScalarNode && child.Line == line {
+				return path
+			}
+			if found := findConfigPathByLine(child, line, path); found != "" {
+				return found
+			}
+		}
+	}
+	return ""
+}
+
+// 匹配yaml类型错误中的行号
+var yamlLineRegex = regexp.MustCompile(`^line (\d+): (.*)$`)
+
+// 将解析框架配置时的类型错误转换为带有文件、行号和路径的ConfigErrors，不是类型错误时返回false
+// 由于合并后的配置经过重新序列化，行号无意义，因此逐个使用原始配置文件重新解析以定位错误
+func explainDecodeError(sources []configSource, err error) (ConfigErrors, bool) {
+	var typeErr *yaml.TypeError
+	if !errors.As(err, &typeErr) {
+		return nil, false
+	}
+
+	var errs ConfigErrors
+	for _, source := range sources {
+		if source.node.Kind == 0 {
+			continue
+		}
+		var sourceErr *yaml.TypeError
+		if !errors.As(source.node.Decode(&config{}), &sourceErr) {
+			continue
+		}
+		for _, msg := range sourceErr.Errors {
+			configErr := ConfigError{File: source.name, Message: msg}
+			if matches := yamlLineRegex.FindStringSubmatch(msg); matches != nil {
+				configErr.Line, _ = strconv.Atoi(matches[1])
+				configErr.Message = matches[2]
+				configErr.Path = findConfigPathByLine(source.node, configErr.Line, "")
+			}
+			errs = append(errs, configErr)
+		}
+	}
+	// 错误来自环境变量覆盖，无法定位文件
+	if len(errs) == 0 {
+		for _, msg := range typeErr.Errors {
+			if matches := yamlLineRegex.FindStringSubmatch(msg); matches != nil {
+				msg = matches[2]
+			}
+			errs = append(errs, ConfigError{Message: msg + " (from " + envOverridePrefix + " environment variable)"})
+		}
+	}
+
+	return errs, true
+}